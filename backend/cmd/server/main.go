@@ -5,11 +5,13 @@ import (
 	"backend/internal/config"
 	"backend/internal/delivery/http/router"
 	"backend/internal/infrastructure/persistence/postgres"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -35,15 +37,42 @@ func main() {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// Build the services shared by the handlers
+	services, err := internal.InitializeServices(db, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize services: %v", err)
+	}
+
 	// Initialize handlers
-	imageHandler, err := internal.InitializeImageHandler(db)
+	imageHandler := internal.InitializeImageHandler(services)
+	quoteHandler := internal.InitializeQuoteHandler(services)
+
+	// Index quotes stored before near-duplicate band hashes were
+	go func() {
+		if err := services.Quotes.BackfillFingerprints(); err != nil {
+			log.Printf("Fingerprint backfill: %v", err)
+		}
+	}()
+
+	syncHandler, syncUseCase := internal.InitializeSyncHandler(db, services)
+
+	// Pull registered Google Sheets in the background
+	go syncUseCase.Start(context.Background(), cfg.SyncPollInterval)
+
+	dailyHandler, dailyUseCase := internal.InitializeDailyHandler(db, cfg, services)
+	tagHandler := internal.InitializeTagHandler(db, services)
+
+	metadataHandler, err := internal.InitializeMetadataHandler(db, cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize image handler: %v", err)
+		log.Fatalf("Failed to initialize metadata handler: %v", err)
 	}
 
+	// Keep dailyQuotes.json covering the days ahead
+	go dailyUseCase.Start(context.Background(), time.Hour)
+
 	// Setup router
 	mux := http.NewServeMux()
-	router.RegisterHandlers(mux, imageHandler)
+	router.RegisterHandlers(mux, imageHandler, quoteHandler, syncHandler, dailyHandler, tagHandler, metadataHandler)
 
 	// Start server
 	log.Printf("Server starting on port %s...", cfg.Port)
@@ -56,6 +85,6 @@ func loadEnv() error {
 	_, currentFile, _, _ := runtime.Caller(0)
 	rootDir := filepath.Join(filepath.Dir(currentFile), "..", "..")
 	envPath := filepath.Join(rootDir, ".env")
-	
+
 	return godotenv.Load(envPath)
 }
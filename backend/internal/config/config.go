@@ -1,9 +1,33 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"time"
+)
+
+// Image URL strategies selected by IMAGE_URL_STRATEGY.
+const (
+	// URLStrategyCDN serves objects from CDN_BASE_URL.
+	URLStrategyCDN = "cdn"
+	// URLStrategyS3 builds virtual-hosted style S3 URLs.
+	URLStrategyS3 = "s3"
+	// URLStrategyPresigned signs a short-lived GET URL on every read.
+	URLStrategyPresigned = "presigned"
+)
+
+// Google Sheets credential sources selected by SHEETS_AUTH. When it is unset
+// inline JSON wins over a file, and application default credentials are the
+// fallback.
+const (
+	// SheetsAuthFile reads a service account key from CREDENTIALS_FILE_PATH.
+	SheetsAuthFile = "file"
+	// SheetsAuthJSON takes the service account key from SHEETS_CREDENTIALS_JSON.
+	SheetsAuthJSON = "json"
+	// SheetsAuthADC uses application default credentials.
+	SheetsAuthADC = "adc"
 )
 
 type Config struct {
@@ -16,6 +40,24 @@ type Config struct {
 	S3Secret        string
 	ImportDirImages string
 	MaxUploadMB     int64
+	URLStrategy     string
+	CDNBaseURL      string
+	PresignTTL      time.Duration
+	// SyncPollInterval is how often the Sheets sync scheduler looks for
+	// due sources.
+	SyncPollInterval time.Duration
+	// DailyQuoteDays is how many days, today included, dailyQuotes.json
+	// covers.
+	DailyQuoteDays int
+	// MetadataSnapshotsKeep is how many versioned snapshots of each
+	// metadata catalog are kept in S3.
+	MetadataSnapshotsKeep int
+
+	SheetsAuth            string
+	SheetsCredentialsFile string
+	SheetsCredentialsJSON string
+	// SheetsEndpoint overrides the Sheets API base URL, for emulators.
+	SheetsEndpoint string
 }
 
 func Load() (*Config, error) {
@@ -31,11 +73,84 @@ func Load() (*Config, error) {
 		S3Secret:        requireEnv("S3_SECRET"),
 		ImportDirImages: requireEnv("IMPORT_DIR_IMAGES"),
 		MaxUploadMB:     maxUploadMB,
+		URLStrategy:     getEnvOrDefault("IMAGE_URL_STRATEGY", URLStrategyS3),
+		CDNBaseURL:      os.Getenv("CDN_BASE_URL"),
+	}
+
+	presignTTL, err := time.ParseDuration(getEnvOrDefault("PRESIGN_TTL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRESIGN_TTL: %w", err)
+	}
+	cfg.PresignTTL = presignTTL
+
+	syncPoll, err := time.ParseDuration(getEnvOrDefault("SYNC_POLL_INTERVAL", "1m"))
+	if err != nil || syncPoll <= 0 {
+		return nil, fmt.Errorf("invalid SYNC_POLL_INTERVAL %q", os.Getenv("SYNC_POLL_INTERVAL"))
+	}
+	cfg.SyncPollInterval = syncPoll
+
+	dailyDays, err := strconv.Atoi(getEnvOrDefault("DAILY_QUOTE_DAYS", "30"))
+	if err != nil || dailyDays <= 0 {
+		return nil, fmt.Errorf("invalid DAILY_QUOTE_DAYS %q", os.Getenv("DAILY_QUOTE_DAYS"))
+	}
+	cfg.DailyQuoteDays = dailyDays
+
+	keepSnapshots, err := strconv.Atoi(getEnvOrDefault("METADATA_SNAPSHOTS_KEEP", "10"))
+	if err != nil || keepSnapshots <= 0 {
+		return nil, fmt.Errorf("invalid METADATA_SNAPSHOTS_KEEP %q", os.Getenv("METADATA_SNAPSHOTS_KEEP"))
+	}
+	cfg.MetadataSnapshotsKeep = keepSnapshots
+
+	if err := loadSheetsAuth(cfg); err != nil {
+		return nil, err
+	}
+
+	switch cfg.URLStrategy {
+	case URLStrategyCDN:
+		if cfg.CDNBaseURL == "" {
+			return nil, fmt.Errorf("CDN_BASE_URL is required when IMAGE_URL_STRATEGY=%s", URLStrategyCDN)
+		}
+	case URLStrategyS3, URLStrategyPresigned:
+	default:
+		return nil, fmt.Errorf("unknown IMAGE_URL_STRATEGY %q", cfg.URLStrategy)
 	}
 
 	return cfg, nil
 }
 
+func loadSheetsAuth(cfg *Config) error {
+	cfg.SheetsCredentialsFile = os.Getenv("CREDENTIALS_FILE_PATH")
+	cfg.SheetsCredentialsJSON = os.Getenv("SHEETS_CREDENTIALS_JSON")
+	cfg.SheetsEndpoint = os.Getenv("SHEETS_API_ENDPOINT")
+	cfg.SheetsAuth = os.Getenv("SHEETS_AUTH")
+
+	if cfg.SheetsAuth == "" {
+		switch {
+		case cfg.SheetsCredentialsJSON != "":
+			cfg.SheetsAuth = SheetsAuthJSON
+		case cfg.SheetsCredentialsFile != "":
+			cfg.SheetsAuth = SheetsAuthFile
+		default:
+			cfg.SheetsAuth = SheetsAuthADC
+		}
+	}
+
+	switch cfg.SheetsAuth {
+	case SheetsAuthFile:
+		if cfg.SheetsCredentialsFile == "" {
+			return fmt.Errorf("CREDENTIALS_FILE_PATH is required when SHEETS_AUTH=%s", SheetsAuthFile)
+		}
+	case SheetsAuthJSON:
+		if !json.Valid([]byte(cfg.SheetsCredentialsJSON)) {
+			return fmt.Errorf("SHEETS_CREDENTIALS_JSON must hold a service account key when SHEETS_AUTH=%s", SheetsAuthJSON)
+		}
+	case SheetsAuthADC:
+	default:
+		return fmt.Errorf("unknown SHEETS_AUTH %q", cfg.SheetsAuth)
+	}
+	return nil
+}
+
 func requireEnv(key string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -49,4 +164,4 @@ func getEnvOrDefault(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-} 
\ No newline at end of file
+}
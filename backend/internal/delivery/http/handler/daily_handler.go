@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"backend/internal/delivery/http/response"
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/usecase/quote"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type DailyHandler struct {
+	dailyUseCase *quote.DailyUseCase
+}
+
+func NewDailyHandler(useCase *quote.DailyUseCase) *DailyHandler {
+	return &DailyHandler{
+		dailyUseCase: useCase,
+	}
+}
+
+// HandleDays lists (GET ?from=&to=&locale=) or creates (POST) quote of the
+// day entries.
+func (h *DailyHandler) HandleDays(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		days, err := h.dailyUseCase.ListDays(q.Get("from"), q.Get("to"), q.Get("locale"))
+		if err != nil {
+			writeDailyError(w, 0, err)
+			return
+		}
+		response.Success(w, days)
+		return
+	}
+
+	var day entity.DailyQuote
+	if err := json.NewDecoder(r.Body).Decode(&day); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if err := h.dailyUseCase.CreateDay(&day); err != nil {
+		writeDailyError(w, 0, err)
+		return
+	}
+	response.JSON(w, http.StatusCreated, response.Response{Success: true, Data: day})
+}
+
+// HandleAutoFill fills the unscheduled days of a range with unused quotes
+// following a tag rotation.
+func (h *DailyHandler) HandleAutoFill(w http.ResponseWriter, r *http.Request) {
+	var req quote.AutoFillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	result, err := h.dailyUseCase.AutoFill(req)
+	if err != nil {
+		writeDailyError(w, 0, err)
+		return
+	}
+	response.Success(w, result)
+}
+
+// HandleDay serves GET, PUT and DELETE /quotes/daily/{id}.
+func (h *DailyHandler) HandleDay(w http.ResponseWriter, r *http.Request) {
+	raw := strings.TrimPrefix(r.URL.Path, "/quotes/daily/")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, fmt.Sprintf("invalid daily quote ID %q", raw))
+		return
+	}
+
+	var day *entity.DailyQuote
+	switch r.Method {
+	case http.MethodGet:
+		day, err = h.dailyUseCase.GetDay(id)
+	case http.MethodPut:
+		var body entity.DailyQuote
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+			return
+		}
+		day, err = h.dailyUseCase.UpdateDay(id, body)
+	case http.MethodDelete:
+		if err := h.dailyUseCase.DeleteDay(id); err != nil {
+			writeDailyError(w, id, err)
+			return
+		}
+		response.Success(w, map[string]interface{}{"id": id})
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err != nil {
+		writeDailyError(w, id, err)
+		return
+	}
+	response.Success(w, day)
+}
+
+func writeDailyError(w http.ResponseWriter, id int, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		response.Error(w, http.StatusNotFound, fmt.Sprintf("daily quote with ID %d not found", id))
+	case errors.Is(err, quote.ErrDailyQuoteTaken):
+		response.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, quote.ErrInvalidDailyQuote):
+		response.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Error handling daily quote: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
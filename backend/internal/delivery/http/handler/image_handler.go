@@ -2,10 +2,22 @@ package handler
 
 import (
 	"backend/internal/delivery/http/response"
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/domain/service"
 	"backend/internal/usecase/image"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ImageHandler struct {
@@ -46,6 +58,17 @@ func (h *ImageHandler) HandleImagesImport(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun")); dryRun {
+		preview, err := h.imageUseCase.PreviewImport(importDir)
+		if err != nil {
+			log.Printf("Error previewing image import: %v", err)
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		response.Success(w, preview)
+		return
+	}
+
 	successCount, failureCount, err := h.imageUseCase.ImportImages(importDir)
 	if err != nil {
 		log.Printf("Error importing images: %v", err)
@@ -58,4 +81,257 @@ func (h *ImageHandler) HandleImagesImport(w http.ResponseWriter, r *http.Request
 		"failure_count": failureCount,
 		"total":         successCount + failureCount,
 	})
-} 
\ No newline at end of file
+}
+
+// HandleImageResource serves GET /images/{id} and GET /images/{id}/file.
+func (h *ImageHandler) HandleImageResource(w http.ResponseWriter, r *http.Request) {
+	id, rest, err := parseImagePath(r.URL.Path)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch rest {
+	case "":
+		h.handleImageGet(w, r, id)
+	case "file":
+		h.handleImageFile(w, r, id)
+	default:
+		response.Error(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (h *ImageHandler) handleImageGet(w http.ResponseWriter, r *http.Request, id uint) {
+	flyer, err := h.imageUseCase.GetImage(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			response.Error(w, http.StatusNotFound, fmt.Sprintf("image with ID %d not found", id))
+			return
+		}
+		log.Printf("Error fetching image %d: %v", id, err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	etag, err := flyerETag(flyer)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	setCacheValidators(w, etag, flyer.UpdatedAt)
+	if notModified(r, etag, flyer.UpdatedAt) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	response.Success(w, flyer)
+}
+
+// flyerETag hashes the stored fields and keys of a flyer. The URLs are left
+// out because presigned ones differ on every request.
+func flyerETag(flyer *entity.Flyer) (string, error) {
+	stored := *flyer
+	stored.Url = ""
+	stored.Renditions = make([]entity.Rendition, len(flyer.Renditions))
+	for i, rendition := range flyer.Renditions {
+		rendition.Url = ""
+		stored.Renditions[i] = rendition
+	}
+	return jsonETag(stored)
+}
+
+// handleImageFile streams the flyer object from S3. ?w= selects the nearest
+// rendition, and Range / conditional requests are honoured.
+func (h *ImageHandler) handleImageFile(w http.ResponseWriter, r *http.Request, id uint) {
+	width := 0
+	if raw := r.URL.Query().Get("w"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid w: must be a positive integer")
+			return
+		}
+		width = n
+	}
+	h.serveImageFile(w, r, id, width, true)
+}
+
+// serveImageFile reads the object's metadata, then its content on condition
+// that the ETag is unchanged. If the object was replaced in between it starts
+// over once when retry is set.
+func (h *ImageHandler) serveImageFile(w http.ResponseWriter, r *http.Request, id uint, width int, retry bool) {
+	key, info, err := h.imageUseCase.ImageFileInfo(id, width)
+	if err != nil {
+		h.writeFileError(w, id, err)
+		return
+	}
+
+	setCacheValidators(w, info.ETag, info.LastModified)
+	w.Header().Set("Accept-Ranges", "bytes")
+	if notModified(r, info.ETag, info.LastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	byteRange := r.Header.Get("Range")
+	if !strings.HasPrefix(byteRange, "bytes=") {
+		byteRange = ""
+	}
+
+	obj, err := h.imageUseCase.OpenImageFile(key, byteRange, info.ETag)
+	if err != nil {
+		if errors.Is(err, service.ErrObjectChanged) && retry {
+			h.serveImageFile(w, r, id, width, false)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidRange) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", info.ContentLength))
+		}
+		h.writeFileError(w, id, err)
+		return
+	}
+	defer obj.Body.Close()
+
+	w.Header().Set("Content-Type", objectContentType(obj.ContentType, key))
+	w.Header().Set("Content-Length", strconv.FormatInt(obj.ContentLength, 10))
+
+	status := http.StatusOK
+	if obj.ContentRange != "" {
+		w.Header().Set("Content-Range", obj.ContentRange)
+		status = http.StatusPartialContent
+	}
+	w.WriteHeader(status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, obj.Body); err != nil {
+		log.Printf("Error streaming image %d: %v", id, err)
+	}
+}
+
+// objectContentType returns the stored Content-Type, or one guessed from the
+// key's extension when S3 only knows the object as generic binary.
+func objectContentType(stored, key string) string {
+	switch stored {
+	case "", "application/octet-stream", "binary/octet-stream":
+		if byExt := mime.TypeByExtension(filepath.Ext(key)); byExt != "" {
+			return byExt
+		}
+	}
+	return stored
+}
+
+func (h *ImageHandler) writeFileError(w http.ResponseWriter, id uint, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		response.Error(w, http.StatusNotFound, fmt.Sprintf("image with ID %d not found", id))
+	case errors.Is(err, service.ErrObjectNotFound):
+		response.Error(w, http.StatusNotFound, fmt.Sprintf("file for image %d not found", id))
+	case errors.Is(err, service.ErrInvalidRange):
+		response.Error(w, http.StatusRequestedRangeNotSatisfiable, err.Error())
+	case errors.Is(err, service.ErrObjectChanged):
+		response.Error(w, http.StatusServiceUnavailable, fmt.Sprintf("file for image %d is being replaced, try again", id))
+	default:
+		log.Printf("Error serving file for image %d: %v", id, err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// parseImagePath splits /images/{id}[/rest] into the flyer ID and whatever
+// follows it.
+func parseImagePath(path string) (uint, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/images/"), "/", 2)
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil || id == 0 {
+		return 0, "", fmt.Errorf("invalid image ID %q", parts[0])
+	}
+	if len(parts) == 1 {
+		return uint(id), "", nil
+	}
+	return uint(id), parts[1], nil
+}
+
+func (h *ImageHandler) HandleImagesList(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseImageFilter(r.URL.Query())
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	flyers, nextCursor, err := h.imageUseCase.ListImages(filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error listing images: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"images":     flyers,
+		"count":      len(flyers),
+		"nextCursor": nextCursor,
+	})
+}
+
+// parseImageFilter reads the listing filters from the query string.
+// sort takes a field name, prefixed with "-" for descending order.
+func parseImageFilter(q url.Values) (repository.ImageFilter, error) {
+	filter := repository.ImageFilter{
+		Tag:         q.Get("tag"),
+		Lang:        q.Get("lang"),
+		Orientation: q.Get("orientation"),
+		FileFormat:  q.Get("fileFormat"),
+		TemplateId:  q.Get("templateId"),
+		Cursor:      q.Get("cursor"),
+	}
+
+	ints := map[string]*int{
+		"minWidth":  &filter.MinWidth,
+		"maxWidth":  &filter.MaxWidth,
+		"minHeight": &filter.MinHeight,
+		"maxHeight": &filter.MaxHeight,
+		"limit":     &filter.Limit,
+	}
+	for name, dst := range ints {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid %s: must be a non-negative integer", name)
+		}
+		*dst = n
+	}
+
+	times := map[string]**time.Time{
+		"createdAfter":  &filter.CreatedAfter,
+		"createdBefore": &filter.CreatedBefore,
+	}
+	for name, dst := range times {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: must be an RFC 3339 timestamp", name)
+		}
+		*dst = &t
+	}
+
+	if sort := q.Get("sort"); sort != "" {
+		filter.Desc = strings.HasPrefix(sort, "-")
+		filter.Sort = strings.TrimPrefix(sort, "-")
+		switch filter.Sort {
+		case repository.ImageSortID, repository.ImageSortCreatedAt, repository.ImageSortWidth, repository.ImageSortHeight:
+		default:
+			return filter, fmt.Errorf("invalid sort: unsupported field %q", filter.Sort)
+		}
+	}
+
+	return filter, nil
+}
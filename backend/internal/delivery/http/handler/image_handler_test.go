@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/domain/service"
+	"backend/internal/usecase/image"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseImageFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    repository.ImageFilter
+		wantErr bool
+	}{
+		{
+			name:  "empty query",
+			query: "",
+			want:  repository.ImageFilter{},
+		},
+		{
+			name:  "filters and descending sort",
+			query: "tag=diwali&lang=en-US&orientation=portrait&minWidth=800&maxHeight=1200&sort=-createdAt&limit=10",
+			want: repository.ImageFilter{
+				Tag:         "diwali",
+				Lang:        "en-US",
+				Orientation: "portrait",
+				MinWidth:    800,
+				MaxHeight:   1200,
+				Sort:        repository.ImageSortCreatedAt,
+				Desc:        true,
+				Limit:       10,
+			},
+		},
+		{
+			name:    "non-numeric width",
+			query:   "minWidth=wide",
+			wantErr: true,
+		},
+		{
+			name:    "bad timestamp",
+			query:   "createdAfter=yesterday",
+			wantErr: true,
+		},
+		{
+			name:    "unknown sort field",
+			query:   "sort=fileName",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			got, err := parseImageFilter(q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseImageFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseImageFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlyerETagIgnoresURLs(t *testing.T) {
+	flyer := &entity.Flyer{
+		Id:         12,
+		Key:        "media/images/12_offer.jpg",
+		Url:        "https://content.s3.amazonaws.com/media/images/12_offer.jpg?X-Amz-Signature=a",
+		Renditions: []entity.Rendition{{Width: 320, Key: "media/images/12_offer_320.jpg", Url: "https://example.com/a"}},
+	}
+	first, err := flyerETag(flyer)
+	if err != nil {
+		t.Fatalf("flyerETag() error = %v", err)
+	}
+
+	flyer.Url = "https://content.s3.amazonaws.com/media/images/12_offer.jpg?X-Amz-Signature=b"
+	flyer.Renditions[0].Url = "https://example.com/b"
+	second, _ := flyerETag(flyer)
+	if first != second {
+		t.Errorf("ETag changed with the URLs: %s != %s", first, second)
+	}
+	if flyer.Url == "" || flyer.Renditions[0].Url == "" {
+		t.Error("flyerETag() cleared the URLs of the flyer itself")
+	}
+
+	flyer.Renditions[0].Key = "media/images/12_offer_320_v2.jpg"
+	third, _ := flyerETag(flyer)
+	if third == second {
+		t.Error("ETag did not change with a rendition key")
+	}
+}
+
+type fakeImageRepo struct {
+	flyer entity.Flyer
+}
+
+func (r *fakeImageRepo) Store(*entity.Flyer) (uint, error) { return 0, nil }
+func (r *fakeImageRepo) Update(*entity.Flyer) error        { return nil }
+func (r *fakeImageRepo) FindAll() ([]entity.Flyer, error)  { return []entity.Flyer{r.flyer}, nil }
+
+func (r *fakeImageRepo) FindByFileNames([]string) ([]entity.Flyer, error) {
+	return nil, nil
+}
+
+func (r *fakeImageRepo) FindByID(id uint) (*entity.Flyer, error) {
+	if id != r.flyer.Id {
+		return nil, repository.ErrNotFound
+	}
+	flyer := r.flyer
+	return &flyer, nil
+}
+
+func (r *fakeImageRepo) List(repository.ImageFilter) ([]entity.Flyer, string, error) {
+	return []entity.Flyer{r.flyer}, "", nil
+}
+
+// fakeObjectStore serves a single object the way S3 does when it was
+// uploaded without a Content-Type. replaced, when set, is served by the next
+// HeadObject only, as if the object changed right after it.
+type fakeObjectStore struct {
+	data     string
+	info     service.ObjectInfo
+	replaced *service.ObjectInfo
+}
+
+func (s *fakeObjectStore) UploadImage(string, string) error    { return nil }
+func (s *fakeObjectStore) UploadMetadata(string, string) error { return nil }
+func (s *fakeObjectStore) ImageKey(fileName string) string     { return "media/images/" + fileName }
+func (s *fakeObjectStore) DeleteObject(string) error           { return nil }
+
+func (s *fakeObjectStore) HeadObject(string) (*service.ObjectInfo, error) {
+	info := s.info
+	if s.replaced != nil {
+		info, s.replaced = *s.replaced, nil
+	}
+	return &info, nil
+}
+
+func (s *fakeObjectStore) GetObject(key, byteRange, ifMatch string) (*service.Object, error) {
+	if ifMatch != "" && ifMatch != s.info.ETag {
+		return nil, service.ErrObjectChanged
+	}
+	obj := &service.Object{ObjectInfo: s.info}
+	body := s.data
+	if byteRange != "" {
+		var start, end int
+		if _, err := fmt.Sscanf(byteRange, "bytes=%d-%d", &start, &end); err != nil || start >= len(s.data) {
+			return nil, service.ErrInvalidRange
+		}
+		if end >= len(s.data) {
+			end = len(s.data) - 1
+		}
+		body = s.data[start : end+1]
+		obj.ContentRange = fmt.Sprintf("bytes %d-%d/%d", start, end, len(s.data))
+	}
+	obj.ContentLength = int64(len(body))
+	obj.Body = io.NopCloser(strings.NewReader(body))
+	return obj, nil
+}
+
+func (s *fakeObjectStore) PresignGetObject(key string, ttl time.Duration) (string, error) {
+	return "", nil
+}
+
+func TestHandleImageFile(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := &fakeObjectStore{
+		data: "0123456789",
+		info: service.ObjectInfo{ContentType: "binary/octet-stream", ContentLength: 10, ETag: `"abc"`, LastModified: modified},
+	}
+	repo := &fakeImageRepo{flyer: entity.Flyer{Id: 12, Key: "media/images/12_offer.jpg"}}
+	h := NewImageHandler(image.NewImageUseCase(repo, store, nil, nil))
+
+	tests := []struct {
+		name         string
+		header       string
+		value        string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{name: "whole file", status: http.StatusOK, body: "0123456789"},
+		{name: "byte range", header: "Range", value: "bytes=2-5", status: http.StatusPartialContent, body: "2345", contentRange: "bytes 2-5/10"},
+		{name: "unsatisfiable range", header: "Range", value: "bytes=20-30", status: http.StatusRequestedRangeNotSatisfiable, contentRange: "bytes */10"},
+		{name: "matching etag", header: "If-None-Match", value: `"abc"`, status: http.StatusNotModified},
+		{name: "not modified since", header: "If-Modified-Since", value: modified.Format(http.TimeFormat), status: http.StatusNotModified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/images/12/file", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			h.HandleImageResource(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Range"); got != tt.contentRange {
+				t.Errorf("expected Content-Range %q, got %q", tt.contentRange, got)
+			}
+			if tt.body == "" {
+				return
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); got != "image/jpeg" {
+				t.Errorf("expected image/jpeg for a binary/octet-stream object, got %q", got)
+			}
+		})
+	}
+}
+
+func TestHandleImageFileReplacedMidRequest(t *testing.T) {
+	store := &fakeObjectStore{
+		data:     "new",
+		info:     service.ObjectInfo{ContentType: "image/jpeg", ContentLength: 3, ETag: `"new"`},
+		replaced: &service.ObjectInfo{ContentType: "image/jpeg", ContentLength: 10, ETag: `"old"`},
+	}
+	repo := &fakeImageRepo{flyer: entity.Flyer{Id: 12, Key: "media/images/12_offer.jpg"}}
+	h := NewImageHandler(image.NewImageUseCase(repo, store, nil, nil))
+
+	w := httptest.NewRecorder()
+	h.HandleImageResource(w, httptest.NewRequest(http.MethodGet, "/images/12/file", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "new" {
+		t.Fatalf("expected the new content, got %d: %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("ETag"); got != `"new"` {
+		t.Errorf("expected the ETag of the served content, got %q", got)
+	}
+	if got := w.Header().Get("Content-Length"); got != "3" {
+		t.Errorf("expected Content-Length 3, got %q", got)
+	}
+}
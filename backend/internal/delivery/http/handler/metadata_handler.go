@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"backend/internal/delivery/http/response"
+	"backend/internal/domain/repository"
+	"backend/internal/usecase/catalog"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type MetadataHandler struct {
+	catalogUseCase *catalog.CatalogUseCase
+}
+
+func NewMetadataHandler(useCase *catalog.CatalogUseCase) *MetadataHandler {
+	return &MetadataHandler{
+		catalogUseCase: useCase,
+	}
+}
+
+// HandleChanges serves GET /metadata/media/{type}/changes?since=N with the
+// items added, updated and deleted after version N, or a resync signal when
+// the change log no longer reaches back that far.
+func (h *MetadataHandler) HandleChanges(w http.ResponseWriter, r *http.Request) {
+	mediaType, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/metadata/media/"), "/changes")
+	if !ok || mediaType == "" || strings.Contains(mediaType, "/") {
+		response.Error(w, http.StatusNotFound, "Not found")
+		return
+	}
+	raw := r.URL.Query().Get("since")
+	since, err := strconv.Atoi(raw)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, fmt.Sprintf("invalid since value %q", raw))
+		return
+	}
+
+	changes, err := h.catalogUseCase.Changes(mediaType, since)
+	switch {
+	case err == nil:
+		response.Success(w, changes)
+	case errors.Is(err, catalog.ErrUnknownCatalog):
+		response.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrNotFound):
+		response.Error(w, http.StatusNotFound, fmt.Sprintf("%s metadata has not been published yet", mediaType))
+	case errors.Is(err, catalog.ErrInvalidSince):
+		response.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Error fetching %s changes: %v", mediaType, err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,605 @@
+package handler
+
+import (
+	"backend/internal/delivery/http/response"
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/usecase/quote"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type QuoteHandler struct {
+	quoteUseCase *quote.QuoteUseCase
+}
+
+func NewQuoteHandler(useCase *quote.QuoteUseCase) *QuoteHandler {
+	return &QuoteHandler{
+		quoteUseCase: useCase,
+	}
+}
+
+// HandleQuoteUpload creates a quote. A body that breaks the validation rules
+// answers 422 listing every field error.
+func (h *QuoteHandler) HandleQuoteUpload(w http.ResponseWriter, r *http.Request) {
+	var q entity.Quote
+	if !decodeQuote(w, r, &q) {
+		return
+	}
+
+	created, err := h.quoteUseCase.CreateQuote(&q)
+	if err != nil {
+		writeQuoteError(w, 0, err)
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"message": "Quote uploaded successfully",
+		"id":      created.Id,
+	})
+}
+
+// HandleQuotesList lists quotes by ID, filtered by author, work, license,
+// tag, lang and attributionRequired.
+func (h *QuoteHandler) HandleQuotesList(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseQuoteFilter(r.URL.Query())
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quotes, nextCursor, err := h.quoteUseCase.ListQuotes(filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error listing quotes: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, map[string]interface{}{
+		"quotes":     quotes,
+		"count":      len(quotes),
+		"nextCursor": nextCursor,
+	})
+}
+
+// parseQuoteFilter reads the listing filters from the query string.
+func parseQuoteFilter(q url.Values) (repository.QuoteFilter, error) {
+	filter := repository.QuoteFilter{
+		Author:  q.Get("author"),
+		Work:    q.Get("work"),
+		License: q.Get("license"),
+		Tag:     q.Get("tag"),
+		Lang:    q.Get("lang"),
+		Cursor:  q.Get("cursor"),
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid limit: must be a non-negative integer")
+		}
+		filter.Limit = n
+	}
+	if raw := q.Get("attributionRequired"); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid attributionRequired: must be true or false")
+		}
+		filter.AttributionRequired = &b
+	}
+	return filter, nil
+}
+
+// HandleSearch runs a full-text search: ?q= with optional tag, lang, limit
+// and cursor.
+func (h *QuoteHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := repository.QuoteSearch{
+		Query:  q.Get("q"),
+		Tag:    q.Get("tag"),
+		Lang:   q.Get("lang"),
+		Cursor: q.Get("cursor"),
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, "invalid limit: must be a non-negative integer")
+			return
+		}
+		search.Limit = n
+	}
+
+	result, err := h.quoteUseCase.SearchQuotes(search)
+	if err != nil {
+		if errors.Is(err, quote.ErrInvalidQuote) || errors.Is(err, repository.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error searching quotes: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, result)
+}
+
+// HandleQuoteResource serves GET, PUT, PATCH and DELETE /quotes/{id}. Writes
+// must name the version they read, in If-Match or the body's "version";
+// DELETE may omit it. /quotes/{id}/translations is served by
+// handleTranslations.
+func (h *QuoteHandler) HandleQuoteResource(w http.ResponseWriter, r *http.Request) {
+	id, sub, err := parseQuotePath(r.URL.Path)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch sub {
+	case "":
+	case "translations":
+		h.handleTranslations(w, r, id)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	var q *entity.Quote
+	switch r.Method {
+	case http.MethodGet:
+		q, err = h.quoteUseCase.GetQuote(id)
+	case http.MethodPut:
+		var body entity.Quote
+		if !decodeQuote(w, r, &body) {
+			return
+		}
+		version, ok := requestVersion(w, r, body.Version)
+		if !ok {
+			return
+		}
+		q, err = h.quoteUseCase.ReplaceQuote(id, version, body)
+	case http.MethodPatch:
+		var body struct {
+			quote.QuotePatch
+			Version int `json:"version"`
+		}
+		if !decodeQuote(w, r, &body) {
+			return
+		}
+		version, ok := requestVersion(w, r, body.Version)
+		if !ok {
+			return
+		}
+		q, err = h.quoteUseCase.PatchQuote(id, version, body.QuotePatch)
+	case http.MethodDelete:
+		version, _ := parseIfMatch(r.Header.Get("If-Match"))
+		if err := h.quoteUseCase.DeleteQuote(id, version); err != nil {
+			writeQuoteError(w, id, err)
+			return
+		}
+		response.Success(w, map[string]interface{}{"id": id})
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err != nil {
+		writeQuoteError(w, id, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(q.Version))
+	response.Success(w, q)
+}
+
+// handleTranslations lists a quote's sibling translations (GET), links
+// another quote into its translation group (POST {"quoteId": n}) or takes
+// the quote out of its group (DELETE).
+func (h *QuoteHandler) handleTranslations(w http.ResponseWriter, r *http.Request, id int) {
+	var (
+		quotes []entity.Quote
+		err    error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		quotes, err = h.quoteUseCase.Translations(id)
+	case http.MethodPost:
+		var body struct {
+			QuoteId int `json:"quoteId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.QuoteId <= 0 {
+			response.Error(w, http.StatusBadRequest, "Invalid JSON payload: quoteId is required")
+			return
+		}
+		quotes, err = h.quoteUseCase.LinkTranslation(id, body.QuoteId)
+	case http.MethodDelete:
+		if err := h.quoteUseCase.UnlinkTranslation(id); err != nil {
+			writeQuoteError(w, id, err)
+			return
+		}
+		response.Success(w, map[string]interface{}{"id": id})
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err != nil {
+		writeQuoteError(w, id, err)
+		return
+	}
+	response.Success(w, quotes)
+}
+
+// parseQuotePath splits /quotes/{id}[/{sub}] into the ID and sub-resource.
+func parseQuotePath(path string) (int, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/quotes/"), "/", 2)
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || id <= 0 {
+		return 0, "", fmt.Errorf("invalid quote ID %q", parts[0])
+	}
+	if len(parts) == 1 {
+		return id, "", nil
+	}
+	return id, parts[1], nil
+}
+
+// requestVersion picks the version a write is based on, preferring
+// If-Match over the body. It answers 428 when neither is given.
+func requestVersion(w http.ResponseWriter, r *http.Request, bodyVersion int) (int, bool) {
+	if header := r.Header.Get("If-Match"); header != "" {
+		version, ok := parseIfMatch(header)
+		if !ok {
+			response.Error(w, http.StatusBadRequest, fmt.Sprintf("invalid If-Match %q", header))
+		}
+		return version, ok
+	}
+	if bodyVersion > 0 {
+		return bodyVersion, true
+	}
+	response.Error(w, http.StatusPreconditionRequired, "the quote version is required in If-Match or the request body")
+	return 0, false
+}
+
+// parseIfMatch reads a version ETag such as "3" or W/"3".
+func parseIfMatch(header string) (int, bool) {
+	tag := strings.Trim(strings.TrimPrefix(strings.TrimSpace(header), "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// decodeQuote reads a quote body into v. A value of the wrong JSON type
+// answers 422 naming the field; malformed JSON answers 400.
+func decodeQuote(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		writeValidationError(w, &quote.ValidationError{Errors: []quote.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("must be of type %s, got %s", jsonTypeName(typeErr.Type.Kind().String()), typeErr.Value),
+		}}})
+		return false
+	}
+	response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+	return false
+}
+
+// jsonTypeName names a Go kind the way the API documents it.
+func jsonTypeName(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "slice":
+		return "array"
+	case kind == "bool":
+		return "boolean"
+	}
+	return kind
+}
+
+func writeValidationError(w http.ResponseWriter, err *quote.ValidationError) {
+	response.JSON(w, http.StatusUnprocessableEntity, response.Response{
+		Success: false,
+		Data:    err.Errors,
+		Error:   "invalid quote",
+	})
+}
+
+func writeQuoteError(w http.ResponseWriter, id int, err error) {
+	var validationErr *quote.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		response.Error(w, http.StatusNotFound, fmt.Sprintf("quote with ID %d not found", id))
+	case errors.Is(err, repository.ErrVersionConflict):
+		response.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, quote.ErrDuplicateQuote), errors.Is(err, quote.ErrTranslationConflict):
+		response.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, quote.ErrInvalidQuote):
+		response.Error(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		log.Printf("Error updating quote %d: %v", id, err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// HandleQuotesImport imports quotes from a Google Sheets link (JSON body), an
+// uploaded .xlsx workbook (multipart "file" plus optional "sheet"), or a
+// text/csv or application/x-ndjson body. Quotes are upserted by natural key;
+// "source", "mirror" and "policy" control reconciliation for files and
+// streams; ?dryRun=true reports the diff without writing anything. An
+// all-or-nothing import that was rolled back answers 422 with the rejected
+// rows.
+func (h *QuoteHandler) HandleQuotesImport(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		h.importExcel(w, r)
+		return
+	case "text/csv":
+		h.importStream(w, r, quote.FormatCSV)
+		return
+	case "application/x-ndjson", "application/ndjson":
+		h.importStream(w, r, quote.FormatNDJSON)
+		return
+	}
+
+	var req quote.SheetImportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, fmt.Sprintf("invalid dryRun value %q", raw))
+			return
+		}
+		req.DryRun = dryRun
+	}
+
+	result, err := h.quoteUseCase.ImportFromSheet(req)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	writeImportResult(w, result)
+}
+
+func (h *QuoteHandler) importExcel(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Failed to retrieve file")
+		return
+	}
+	defer file.Close()
+
+	opts, err := importOptionsFromValues(r.Form)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.quoteUseCase.ImportFromExcel(file, r.FormValue("sheet"), mappingFromValues(r.Form), opts)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	writeImportResult(w, result)
+}
+
+// importStream streams a CSV or NDJSON body into the importer. The CSV
+// delimiter and the header names for each field come from the query string.
+func (h *QuoteHandler) importStream(w http.ResponseWriter, r *http.Request, format string) {
+	q := r.URL.Query()
+	importOpts, err := importOptionsFromValues(q)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts := quote.StreamOptions{
+		ImportOptions: importOpts,
+		Format:        format,
+		Mapping:       mappingFromValues(q),
+	}
+
+	if raw := q.Get("delimiter"); raw != "" {
+		delimiter, err := parseDelimiter(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts.Delimiter = delimiter
+	}
+
+	result, err := h.quoteUseCase.ImportStream(r.Body, opts)
+	if err != nil {
+		writeImportError(w, err)
+		return
+	}
+
+	writeImportResult(w, result)
+}
+
+// mappingFromValues reads header names for each quote field from
+// textField, tagsField, langField, authorField and idField.
+func mappingFromValues(values url.Values) entity.ColumnMapping {
+	return entity.ColumnMapping{
+		Text:   values.Get("textField"),
+		Tags:   values.Get("tagsField"),
+		Lang:   values.Get("langField"),
+		Author: values.Get("authorField"),
+		Id:     values.Get("idField"),
+	}
+}
+
+// importOptionsFromValues reads the import source, policy and the mirror and
+// dryRun flags.
+func importOptionsFromValues(values url.Values) (quote.ImportOptions, error) {
+	opts := quote.ImportOptions{Source: values.Get("source"), Policy: values.Get("policy")}
+	for name, flag := range map[string]*bool{"mirror": &opts.Mirror, "dryRun": &opts.DryRun} {
+		if raw := values.Get(name); raw != "" {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s value %q", name, raw)
+			}
+			*flag = v
+		}
+	}
+	return opts, nil
+}
+
+// parseDelimiter accepts a single character or the name "tab".
+func parseDelimiter(raw string) (rune, error) {
+	if strings.EqualFold(raw, "tab") || raw == `\t` {
+		return '\t', nil
+	}
+	runes := []rune(raw)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("invalid delimiter %q: must be a single character", raw)
+	}
+	return runes[0], nil
+}
+
+// HandleProfiles lists (GET) or saves (POST) named import profiles.
+func (h *QuoteHandler) HandleProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		profiles, err := h.quoteUseCase.ListProfiles()
+		if err != nil {
+			log.Printf("Error listing import profiles: %v", err)
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		response.Success(w, profiles)
+		return
+	}
+
+	var profile entity.ImportProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	profile.Id = 0
+
+	if err := h.quoteUseCase.SaveProfile(&profile); err != nil {
+		writeImportError(w, err)
+		return
+	}
+	response.Success(w, profile)
+}
+
+// HandleBundle exports the catalog as a quotes bundle (GET) or imports one
+// (POST), upserting its quotes by ID. An invalid bundle answers 422 listing
+// every field error.
+func (h *QuoteHandler) HandleBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		bundle, err := h.quoteUseCase.ExportBundle()
+		if err != nil {
+			log.Printf("Error exporting quotes bundle: %v", err)
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="quotes.json"`)
+		response.JSON(w, http.StatusOK, bundle)
+		return
+	}
+
+	result, err := h.quoteUseCase.ImportBundle(r.Body)
+	var bundleErr *quote.BundleError
+	switch {
+	case errors.As(err, &bundleErr):
+		response.JSON(w, http.StatusUnprocessableEntity, response.Response{
+			Success: false,
+			Data:    bundleErr.Errors,
+			Error:   "invalid quotes bundle",
+		})
+	case errors.Is(err, quote.ErrDuplicateQuote):
+		response.Error(w, http.StatusConflict, err.Error())
+	case err != nil:
+		log.Printf("Error importing quotes bundle: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	default:
+		response.Success(w, result)
+	}
+}
+
+// HandleDuplicates reports clusters of near-duplicate quotes. ?threshold
+// overrides QUOTE_DUPLICATE_THRESHOLD.
+func (h *QuoteHandler) HandleDuplicates(w http.ResponseWriter, r *http.Request) {
+	var threshold float64
+	if raw := r.URL.Query().Get("threshold"); raw != "" {
+		t, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, fmt.Sprintf("invalid threshold %q", raw))
+			return
+		}
+		threshold = t
+	}
+
+	clusters, err := h.quoteUseCase.FindDuplicates(threshold)
+	if errors.Is(err, quote.ErrInvalidQuote) {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error finding duplicate quotes: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, clusters)
+}
+
+func writeImportResult(w http.ResponseWriter, result *quote.ImportResult) {
+	if result.RolledBack {
+		response.JSON(w, http.StatusUnprocessableEntity, response.Response{
+			Success: false,
+			Data:    result,
+			Error:   fmt.Sprintf("%d rows were rejected; nothing was imported", result.Failed),
+		})
+		return
+	}
+	response.Success(w, result)
+}
+
+func writeImportError(w http.ResponseWriter, err error) {
+	var headerErr *quote.HeaderError
+	if errors.As(err, &headerErr) {
+		response.JSON(w, http.StatusUnprocessableEntity, response.Response{
+			Success: false,
+			Data:    headerErr,
+			Error:   headerErr.Error(),
+		})
+		return
+	}
+	if errors.Is(err, quote.ErrInvalidSheetLink) || errors.Is(err, quote.ErrInvalidSpreadsheet) || errors.Is(err, quote.ErrInvalidImport) {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("Error importing quotes: %v", err)
+	response.Error(w, http.StatusInternalServerError, err.Error())
+}
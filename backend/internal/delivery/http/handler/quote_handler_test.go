@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/usecase/quote"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	for header, want := range map[string]int{
+		`"3"`:    3,
+		`W/"12"`: 12,
+		` "7" `:  7,
+		`"abc"`:  0,
+		`"0"`:    0,
+		`*`:      0,
+	} {
+		got, ok := parseIfMatch(header)
+		if got != want || ok != (want > 0) {
+			t.Errorf("parseIfMatch(%q) = %d, %v; want %d", header, got, ok, want)
+		}
+	}
+}
+
+func TestDecodeQuoteReportsTypeErrors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(`{"text": "Be kind.", "tags": "kindness"}`))
+	w := httptest.NewRecorder()
+
+	var q entity.Quote
+	if decodeQuote(w, r, &q) {
+		t.Fatal("expected the body to be rejected")
+	}
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+	var body struct {
+		Data []quote.FieldError `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != 1 || body.Data[0].Field != "tags" || body.Data[0].Rule != "type" {
+		t.Errorf("unexpected field errors: %+v", body.Data)
+	}
+}
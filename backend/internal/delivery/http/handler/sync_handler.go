@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"backend/internal/delivery/http/response"
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/usecase/quote"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SyncHandler struct {
+	syncUseCase *quote.SyncUseCase
+}
+
+func NewSyncHandler(useCase *quote.SyncUseCase) *SyncHandler {
+	return &SyncHandler{
+		syncUseCase: useCase,
+	}
+}
+
+// HandleSources lists (GET) or registers (POST) Sheets sync sources.
+func (h *SyncHandler) HandleSources(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		sources, err := h.syncUseCase.ListSources()
+		if err != nil {
+			log.Printf("Error listing sync sources: %v", err)
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		response.Success(w, sources)
+		return
+	}
+
+	var source entity.SyncSource
+	if err := json.NewDecoder(r.Body).Decode(&source); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if err := h.syncUseCase.AddSource(&source); err != nil {
+		writeSyncError(w, err)
+		return
+	}
+	response.JSON(w, http.StatusCreated, response.Response{Success: true, Data: source})
+}
+
+// HandleSource serves GET, PATCH and DELETE /quotes/sync/sources/{id} and
+// POST /quotes/sync/sources/{id}/run. PATCH takes {"paused": bool} to pause or
+// resume the source.
+func (h *SyncHandler) HandleSource(w http.ResponseWriter, r *http.Request) {
+	id, rest, err := parseSyncSourcePath(r.URL.Path)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		source, err := h.syncUseCase.GetSource(id)
+		if err != nil {
+			writeSyncError(w, err)
+			return
+		}
+		response.Success(w, source)
+	case rest == "" && r.Method == http.MethodPatch:
+		var body struct {
+			Paused *bool `json:"paused"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+			return
+		}
+		if body.Paused == nil {
+			response.Error(w, http.StatusBadRequest, "paused is required")
+			return
+		}
+		source, err := h.syncUseCase.SetPaused(id, *body.Paused)
+		if err != nil {
+			writeSyncError(w, err)
+			return
+		}
+		response.Success(w, source)
+	case rest == "" && r.Method == http.MethodDelete:
+		if err := h.syncUseCase.RemoveSource(id); err != nil {
+			writeSyncError(w, err)
+			return
+		}
+		response.Success(w, map[string]interface{}{"id": id})
+	case rest == "run" && r.Method == http.MethodPost:
+		source, err := h.syncUseCase.RunSource(id)
+		if err != nil {
+			writeSyncError(w, err)
+			return
+		}
+		response.Success(w, source)
+	case rest == "" || rest == "run":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		response.Error(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func parseSyncSourcePath(path string) (int, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/quotes/sync/sources/"), "/", 2)
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || id <= 0 {
+		return 0, "", fmt.Errorf("invalid sync source ID %q", parts[0])
+	}
+	if len(parts) == 1 {
+		return id, "", nil
+	}
+	return id, parts[1], nil
+}
+
+func writeSyncError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		response.Error(w, http.StatusNotFound, "sync source not found")
+	case errors.Is(err, quote.ErrInvalidSheetLink), errors.Is(err, quote.ErrInvalidImport):
+		response.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Error handling sync source: %v", err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
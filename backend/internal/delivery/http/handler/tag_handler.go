@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"backend/internal/delivery/http/response"
+	"backend/internal/usecase/tag"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TagHandler struct {
+	tagUseCase *tag.TagUseCase
+}
+
+func NewTagHandler(useCase *tag.TagUseCase) *TagHandler {
+	return &TagHandler{
+		tagUseCase: useCase,
+	}
+}
+
+// HandleBulk serves POST /tags/{rename,merge,delete,add,remove}. ?dryRun=true
+// or "dryRun" in the body counts the items that would change without
+// writing anything.
+func (h *TagHandler) HandleBulk(w http.ResponseWriter, r *http.Request) {
+	var req tag.BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	req.Op = strings.TrimPrefix(r.URL.Path, "/tags/")
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, fmt.Sprintf("invalid dryRun value %q", raw))
+			return
+		}
+		req.DryRun = dryRun
+	}
+
+	result, err := h.tagUseCase.Apply(req)
+	if err != nil {
+		if errors.Is(err, tag.ErrInvalidOperation) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error applying bulk tag %s: %v", req.Op, err)
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, result)
+}
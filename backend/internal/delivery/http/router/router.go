@@ -7,7 +7,7 @@ import (
 	"net/http"
 )
 
-func RegisterHandlers(mux *http.ServeMux, imageHandler *handler.ImageHandler) {
+func RegisterHandlers(mux *http.ServeMux, imageHandler *handler.ImageHandler, quoteHandler *handler.QuoteHandler, syncHandler *handler.SyncHandler, dailyHandler *handler.DailyHandler, tagHandler *handler.TagHandler, metadataHandler *handler.MetadataHandler) {
 	// Create middleware chain
 	chain := func(h http.Handler) http.Handler {
 		return middleware.ErrorHandler(
@@ -15,6 +15,94 @@ func RegisterHandlers(mux *http.ServeMux, imageHandler *handler.ImageHandler) {
 		)
 	}
 
+	// Quote routes
+	mux.Handle("/quotes/import", chain(
+		middleware.QuotesImport(
+			http.HandlerFunc(quoteHandler.HandleQuotesImport),
+		),
+	))
+
+	mux.Handle("/quotes/import/profiles", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet:  quoteHandler.HandleProfiles,
+			http.MethodPost: quoteHandler.HandleProfiles,
+		}),
+	))
+
+	mux.Handle("/quotes/bundle", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet:  quoteHandler.HandleBundle,
+			http.MethodPost: quoteHandler.HandleBundle,
+		}),
+	))
+
+	mux.Handle("/quotes/search", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet: quoteHandler.HandleSearch,
+		}),
+	))
+
+	mux.Handle("/quotes/duplicates", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet: quoteHandler.HandleDuplicates,
+		}),
+	))
+
+	mux.Handle("/quotes/sync/sources", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet:  syncHandler.HandleSources,
+			http.MethodPost: syncHandler.HandleSources,
+		}),
+	))
+
+	mux.Handle("/quotes/sync/sources/", chain(
+		http.HandlerFunc(syncHandler.HandleSource),
+	))
+
+	mux.Handle("/quotes/daily", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet:  dailyHandler.HandleDays,
+			http.MethodPost: dailyHandler.HandleDays,
+		}),
+	))
+
+	mux.Handle("/quotes/daily/autofill", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodPost: dailyHandler.HandleAutoFill,
+		}),
+	))
+
+	mux.Handle("/quotes/daily/", chain(
+		http.HandlerFunc(dailyHandler.HandleDay),
+	))
+
+	mux.Handle("/quotes", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet: quoteHandler.HandleQuotesList,
+			http.MethodPost: middleware.QuoteJSONValidator(
+				http.HandlerFunc(quoteHandler.HandleQuoteUpload),
+			).ServeHTTP,
+		}),
+	))
+
+	mux.Handle("/quotes/", chain(
+		http.HandlerFunc(quoteHandler.HandleQuoteResource),
+	))
+
+	// Bulk tag routes across quotes and flyers
+	mux.Handle("/tags/", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodPost: tagHandler.HandleBulk,
+		}),
+	))
+
+	// Incremental sync of the published metadata catalogs
+	mux.Handle("/metadata/media/", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet: metadataHandler.HandleChanges,
+		}),
+	))
+
 	// Image routes with middleware chain
 	mux.Handle("/images/import", chain(
 		middleware.ImagesImport(
@@ -24,9 +112,31 @@ func RegisterHandlers(mux *http.ServeMux, imageHandler *handler.ImageHandler) {
 
 	mux.Handle("/images", chain(
 		middleware.ImageAndMethodValidator(
-			http.HandlerFunc(imageHandler.HandleImagesUpload),
+			routeByMethod(map[string]http.HandlerFunc{
+				http.MethodGet:  imageHandler.HandleImagesList,
+				http.MethodPost: imageHandler.HandleImagesUpload,
+			}),
 		),
 	))
 
+	mux.Handle("/images/", chain(
+		routeByMethod(map[string]http.HandlerFunc{
+			http.MethodGet:  imageHandler.HandleImageResource,
+			http.MethodHead: imageHandler.HandleImageResource,
+		}),
+	))
+
 	log.Println("Routes registered successfully")
-} 
\ No newline at end of file
+}
+
+// routeByMethod dispatches a request to the handler registered for its method.
+func routeByMethod(handlers map[string]http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, ok := handlers[r.Method]
+		if !ok {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h(w, r)
+	})
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+    "encoding/json"
+    "time"
+)
+
+// Metadata catalogs published to S3.
+const (
+    CatalogImages      = "images"
+    CatalogQuotes      = "quotes"
+    CatalogDailyQuotes = "dailyQuotes"
+)
+
+// CatalogVersion is the version counter of one metadata catalog. Every
+// published change takes the next number.
+type CatalogVersion struct {
+    Catalog string `gorm:"primaryKey"`
+    Version int    `gorm:"not null"`
+}
+
+// Change log operations recorded in CatalogChange.Op.
+const (
+    ChangeAdd    = "add"
+    ChangeUpdate = "update"
+    ChangeDelete = "delete"
+)
+
+// CatalogSnapshot records an immutable published snapshot of a catalog,
+// such as quotesMetadata.v57.json, and the delta file listing what changed
+// since the previous version. Hash and Size describe the snapshot file;
+// ContentHash covers only the catalog items, so republishing unchanged items
+// does not take a new version. ItemHashes maps each item ID to the hash of
+// the item, to diff the next version against. Current is set once the
+// pointer names the snapshot; a snapshot whose files failed to upload is
+// superseded by the next publish even if the items did not change.
+type CatalogSnapshot struct {
+    Id          int               `json:"-" gorm:"primaryKey;autoIncrement"`
+    Catalog     string            `json:"catalog" gorm:"not null;uniqueIndex:idx_catalog_snapshots_version"`
+    Version     int               `json:"version" gorm:"not null;uniqueIndex:idx_catalog_snapshots_version"`
+    File        string            `json:"file" gorm:"not null"`
+    Hash        string            `json:"hash" gorm:"not null"`
+    Size        int64             `json:"size"`
+    Delta       string            `json:"delta,omitempty"`
+    ContentHash string            `json:"-" gorm:"not null"`
+    ItemHashes  map[string]string `json:"-" gorm:"serializer:json"`
+    PublishedAt time.Time         `json:"publishedAt"`
+    Current     bool              `json:"-" gorm:"not null"`
+}
+
+// CatalogChange is one entry of a catalog's change log: an item added,
+// updated or deleted by a version. Item holds the item as published, and is
+// empty for deletes.
+type CatalogChange struct {
+    Id      int64           `json:"-" gorm:"primaryKey;autoIncrement"`
+    Catalog string          `json:"-" gorm:"not null;index:idx_catalog_changes_version"`
+    Version int             `json:"version" gorm:"not null;index:idx_catalog_changes_version"`
+    ItemId  string          `json:"id" gorm:"not null"`
+    Op      string          `json:"op" gorm:"not null"`
+    Item    json.RawMessage `json:"item,omitempty" gorm:"type:jsonb"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// DailyQuote schedules a quote as the quote of the day for one locale. Date
+// is a calendar date such as "2024-04-01"; each date and locale has at most
+// one entry.
+type DailyQuote struct {
+    Id      int    `json:"id" gorm:"primaryKey;autoIncrement"`
+    Date    string `json:"date" gorm:"size:10;not null;uniqueIndex:idx_daily_quotes_date_locale"`
+    Locale  string `json:"locale" gorm:"not null;uniqueIndex:idx_daily_quotes_date_locale"`
+    QuoteId int    `json:"quoteId" gorm:"not null;index"`
+
+    // AutoFilled marks entries chosen by auto-fill rather than an editor,
+    // and Tag the rotation tag the quote was picked for.
+    AutoFilled bool   `json:"autoFilled"`
+    Tag        string `json:"tag,omitempty"`
+
+    CreatedAt time.Time `json:"createdAt"`
+    UpdatedAt time.Time `json:"updatedAt"`
+
+    // Quote is filled in for the published schedule and not stored.
+    Quote *Quote `json:"quote,omitempty" gorm:"-"`
+}
@@ -1,24 +1,40 @@
 package entity
 
+import "time"
+
 type Flyer struct {
-    Id     uint   `json:"id" gorm:"primaryKey;autoIncrement"`
-    Design Design `json:"design" gorm:"embedded"`
-    Lang   string `json:"lang"`
-    Url    string `json:"url"`
+    Id         uint        `json:"id" gorm:"primaryKey;autoIncrement;index:idx_flyers_created_at_id,priority:2"`
+    Design     Design      `json:"design" gorm:"embedded"`
+    Lang       string      `json:"lang" gorm:"index"`
+    // Key is the S3 object key; Url is derived from it when the flyer is
+    // read or published and is never stored.
+    Key        string      `json:"key"`
+    Url        string      `json:"url" gorm:"-"`
+    Renditions []Rendition `json:"renditions" gorm:"serializer:json"`
+    CreatedAt  time.Time   `json:"createdAt" gorm:"index:idx_flyers_created_at_id,priority:1"`
+    UpdatedAt  time.Time   `json:"updatedAt"`
+}
+
+// Rendition is a downscaled copy of a flyer stored next to the original.
+type Rendition struct {
+    Width  int    `json:"width"`
+    Height int    `json:"height"`
+    Key    string `json:"key"`
+    Url    string `json:"url,omitempty"`
 }
 
 type Design struct {
-    TemplateId  string     `json:"templateId" gorm:"column:template_id"`
+    TemplateId  string     `json:"templateId" gorm:"column:template_id;index"`
     Resolution  Resolution `json:"resolution" gorm:"embedded"`
     Type        string     `json:"type"`
     Tags        []string   `json:"tags" gorm:"serializer:json"`
-    FileFormat  string     `json:"fileFormat" gorm:"column:file_format"`
-    Orientation string     `json:"orientation"`
+    FileFormat  string     `json:"fileFormat" gorm:"column:file_format;index"`
+    Orientation string     `json:"orientation" gorm:"index"`
     FileName    string     `json:"fileName"`
 }
 
 type Resolution struct {
-    Width  int `json:"width" gorm:"column:width"`
-    Height int `json:"height" gorm:"column:height"`
+    Width  int `json:"width" gorm:"column:width;index"`
+    Height int `json:"height" gorm:"column:height;index"`
     Unit   int `json:"unit" gorm:"column:unit"`
-} 
\ No newline at end of file
+}
@@ -0,0 +1,25 @@
+package entity
+
+// ImportProfile is a saved sheet range and column mapping that quote imports
+// can refer to by name.
+type ImportProfile struct {
+    Id      int           `json:"id" gorm:"primaryKey;autoIncrement"`
+    Name    string        `json:"name" gorm:"uniqueIndex;not null"`
+    Range   string        `json:"range"`
+    Mapping ColumnMapping `json:"mapping" gorm:"serializer:json"`
+}
+
+// ColumnMapping names the header (or JSON key) holding each quote field.
+// Empty fields fall back to the field's own name.
+type ColumnMapping struct {
+    Text   string `json:"text,omitempty"`
+    Tags   string `json:"tags,omitempty"`
+    Lang   string `json:"lang,omitempty"`
+    Author string `json:"author,omitempty"`
+    Id     string `json:"id,omitempty"`
+
+    Work                string `json:"work,omitempty"`
+    Year                string `json:"year,omitempty"`
+    License             string `json:"license,omitempty"`
+    AttributionRequired string `json:"attributionRequired,omitempty"`
+}
@@ -1,8 +1,60 @@
 package entity
 
+import (
+    "github.com/lib/pq"
+    "gorm.io/gorm"
+)
+
 type Quote struct {
-    Id   int      `json:"id" gorm:"primaryKey;autoIncrement"`
-    Text string   `json:"text"`
-    Tags []string `json:"tags" gorm:"serializer:json"`
-    Lang string   `json:"lang"`
-} 
\ No newline at end of file
+    Id     int      `json:"id" gorm:"primaryKey;autoIncrement"`
+    Text   string   `json:"text"`
+    Tags   []string `json:"tags" gorm:"serializer:json"`
+    Lang   string   `json:"lang"`
+    Author string   `json:"author,omitempty"`
+
+    // Work is the book, speech or other work the quote comes from, and Year
+    // when it appeared. License names the terms the quote may be used
+    // under; AttributionRequired says whether displays must credit the
+    // author.
+    Work                string `json:"work,omitempty"`
+    Year                int    `json:"year,omitempty"`
+    License             string `json:"license,omitempty"`
+    AttributionRequired bool   `json:"attributionRequired,omitempty"`
+
+    // ExternalId is the identifier the import source uses for the quote.
+    ExternalId string `json:"externalId,omitempty" gorm:"index"`
+    // TranslationGroup links the same quote across languages. A group holds
+    // at most one quote per language.
+    TranslationGroup string `json:"translationGroup,omitempty" gorm:"index"`
+    // Translations lists the other quotes of the group. It is filled in for
+    // the published metadata and not stored.
+    Translations []QuoteTranslation `json:"translations,omitempty" gorm:"-"`
+
+    // NaturalKey identifies the quote across re-imports: the source ID plus
+    // language when there is one, otherwise a hash of the normalised text
+    // plus language. It is unique among non-empty values.
+    NaturalKey string `json:"-"`
+    // ImportSource and ImportRun record which import last wrote the quote,
+    // so a mirror import can remove quotes that left the source.
+    ImportSource string `json:"-" gorm:"index"`
+    ImportRun    string `json:"-"`
+    // Fingerprint is the MinHash signature of the quote's folded text, used
+    // to find near duplicates.
+    Fingerprint []uint32 `json:"-" gorm:"serializer:json"`
+    // Bands are the locality-sensitive hashes of the fingerprint's bands.
+    // Quotes sharing any of them are compared for near duplicates. They are
+    // NULL for quotes stored before bands were.
+    Bands pq.Int64Array `json:"-" gorm:"type:bigint[]"`
+
+    // Version starts at 1 and is bumped by every update; writers must send
+    // the version they read.
+    Version int `json:"version" gorm:"not null;default:1"`
+    // DeletedAt marks soft-deleted quotes, which GORM hides from queries.
+    DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// QuoteTranslation points at a sibling translation of a quote.
+type QuoteTranslation struct {
+    Id   int    `json:"id"`
+    Lang string `json:"lang"`
+}
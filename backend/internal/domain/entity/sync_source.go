@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Statuses recorded in SyncSource.LastStatus.
+const (
+    SyncStatusOK        = "ok"
+    SyncStatusUnchanged = "unchanged"
+    SyncStatusPartial   = "partial"
+    SyncStatusFailed    = "failed"
+)
+
+// SyncSource is a Google Sheet that is imported into the quotes on a
+// schedule. Schedule is a duration such as "30m", "@every 2h", "@hourly" or
+// "@daily".
+type SyncSource struct {
+    Id       int    `json:"id" gorm:"primaryKey;autoIncrement"`
+    SheetURL string `json:"sheetUrl" gorm:"not null"`
+    Range    string `json:"range,omitempty"`
+    Profile  string `json:"profile,omitempty"`
+    AllTabs  bool   `json:"allTabs,omitempty"`
+    Mirror   bool   `json:"mirror,omitempty"`
+    Schedule string `json:"schedule" gorm:"not null"`
+    Paused   bool   `json:"paused"`
+
+    // ContentHash is the hash of the sheet contents last imported, so
+    // unchanged sheets are not reconciled again.
+    ContentHash string         `json:"contentHash,omitempty"`
+    NextRunAt   time.Time      `json:"nextRunAt" gorm:"index"`
+    LastRunAt   *time.Time     `json:"lastRunAt,omitempty"`
+    LastStatus  string         `json:"lastStatus,omitempty"`
+    LastError   string         `json:"lastError,omitempty"`
+    LastResult  *SyncRunResult `json:"lastResult,omitempty" gorm:"serializer:json"`
+    CreatedAt   time.Time      `json:"createdAt"`
+    UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// SyncRunResult summarises the import a sync run performed.
+type SyncRunResult struct {
+    Total     int `json:"total"`
+    Created   int `json:"created"`
+    Updated   int `json:"updated"`
+    Unchanged int `json:"unchanged"`
+    Deleted   int `json:"deleted"`
+    Failed    int `json:"failed"`
+    // DurationMs is how long the run took, reading the sheet included.
+    DurationMs int64 `json:"durationMs"`
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "backend/internal/domain/entity"
+
+type CatalogRepository interface {
+    // Transaction runs fn against a repository bound to one transaction.
+    Transaction(fn func(repo CatalogRepository) error) error
+    // ReadOnly runs fn against a repository bound to one read-only
+    // REPEATABLE READ transaction, so all its reads see the same data.
+    ReadOnly(fn func(repo CatalogRepository) error) error
+    // Lock holds a catalog's publish lock until the transaction ends, so
+    // versions of one catalog are published one at a time.
+    Lock(catalog string) error
+    // NextVersion atomically bumps a catalog's version counter and returns
+    // the new version, starting at 1.
+    NextVersion(catalog string) (int, error)
+    // StoreSnapshot records a snapshot together with the changes of its
+    // version, in one transaction.
+    StoreSnapshot(snapshot *entity.CatalogSnapshot, changes []entity.CatalogChange) error
+    // MarkCurrent records that the pointer of a catalog names version.
+    MarkCurrent(catalog string, version int) error
+    // LatestSnapshot returns the newest snapshot of a catalog, or
+    // ErrNotFound when none was published.
+    LatestSnapshot(catalog string) (*entity.CatalogSnapshot, error)
+    // ListSnapshots returns the kept snapshots of a catalog, oldest first.
+    ListSnapshots(catalog string) ([]entity.CatalogSnapshot, error)
+    // PruneSnapshots deletes the records of all but the newest keep
+    // snapshots of a catalog and returns them, so their files can be
+    // removed. The change log is compacted to the versions after the oldest
+    // kept snapshot.
+    PruneSnapshots(catalog string, keep int) ([]entity.CatalogSnapshot, error)
+    // FindChanges returns the changes of the versions after since, oldest
+    // first.
+    FindChanges(catalog string, since int) ([]entity.CatalogChange, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "backend/internal/domain/entity"
+
+type DailyQuoteRepository interface {
+    Store(day *entity.DailyQuote) (int, error)
+    Update(day *entity.DailyQuote) error
+    Delete(id int) error
+    FindByID(id int) (*entity.DailyQuote, error)
+    // FindRange returns the entries dated from one date to another,
+    // inclusive, ordered by date and locale. An empty locale matches all.
+    FindRange(from, to, locale string) ([]entity.DailyQuote, error)
+    // UsedQuoteIDs returns the IDs of every quote ever scheduled for locale.
+    UsedQuoteIDs(locale string) ([]int, error)
+}
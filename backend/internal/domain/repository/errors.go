@@ -0,0 +1,16 @@
+package repository
+
+import "errors"
+
+var (
+    // ErrNotFound is returned when no record matches the requested ID.
+    ErrNotFound = errors.New("record not found")
+
+    // ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+    // or was issued for a different sort order.
+    ErrInvalidCursor = errors.New("invalid cursor")
+
+    // ErrVersionConflict is returned when a record changed since the version
+    // the caller read.
+    ErrVersionConflict = errors.New("version conflict")
+)
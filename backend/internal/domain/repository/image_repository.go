@@ -1,10 +1,48 @@
 package repository
 
-import "backend/internal/domain/entity"
+import (
+    "backend/internal/domain/entity"
+    "time"
+)
+
+// Sort keys accepted by ImageFilter.Sort.
+const (
+    ImageSortID        = "id"
+    ImageSortCreatedAt = "createdAt"
+    ImageSortWidth     = "width"
+    ImageSortHeight    = "height"
+)
+
+// DefaultImagePageSize is the page size of a flyer listing without a limit.
+const DefaultImagePageSize = 20
+
+// ImageFilter narrows and orders a flyer listing. Zero values are ignored.
+type ImageFilter struct {
+    Tag           string
+    Lang          string
+    Orientation   string
+    FileFormat    string
+    TemplateId    string
+    MinWidth      int
+    MaxWidth      int
+    MinHeight     int
+    MaxHeight     int
+    CreatedAfter  *time.Time
+    CreatedBefore *time.Time
+    Sort          string
+    Desc          bool
+    Cursor        string
+    Limit         int
+}
 
 type ImageRepository interface {
     Store(image *entity.Flyer) (uint, error)
     Update(image *entity.Flyer) error
     FindByID(id uint) (*entity.Flyer, error)
     FindAll() ([]entity.Flyer, error)
-} 
\ No newline at end of file
+    // FindByFileNames returns the flyers imported from any of the file names.
+    FindByFileNames(names []string) ([]entity.Flyer, error)
+    // List returns one page of flyers matching the filter together with the
+    // cursor for the next page, which is empty on the last page.
+    List(filter ImageFilter) ([]entity.Flyer, string, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "backend/internal/domain/entity"
+
+type ImportProfileRepository interface {
+    // Save creates the profile or replaces the one with the same name.
+    Save(profile *entity.ImportProfile) error
+    FindByName(name string) (*entity.ImportProfile, error)
+    FindAll() ([]entity.ImportProfile, error)
+}
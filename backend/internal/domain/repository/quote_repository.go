@@ -2,9 +2,81 @@ package repository
 
 import "backend/internal/domain/entity"
 
+// QuoteFilter narrows a quote listing, which is ordered by ID. Author, Work
+// and License match case-insensitively. Zero values are ignored.
+type QuoteFilter struct {
+    Author              string
+    Work                string
+    License             string
+    Tag                 string
+    Lang                string
+    AttributionRequired *bool
+    Cursor              string
+    Limit               int
+}
+
+// QuoteSearch is a full-text query over quote text, author and work. Lang
+// filters the results and picks the text-search configuration; without it
+// every configuration is tried. Results are ordered by rank.
+type QuoteSearch struct {
+    Query  string
+    Tag    string
+    Lang   string
+    Cursor string
+    Limit  int
+}
+
+// QuoteSearchHit is a quote matching a search, with its rank and a snippet
+// of its text in which the matches are wrapped in <mark> tags.
+type QuoteSearchHit struct {
+    Quote   entity.Quote
+    Rank    float64
+    Snippet string
+}
+
 type QuoteRepository interface {
     Store(quote *entity.Quote) (int, error)
+    StoreBatch(quotes []entity.Quote) error
     Update(quote *entity.Quote) error
+    // UpdateVersion saves quote if its stored version is still version and
+    // bumps quote.Version. It returns ErrVersionConflict otherwise.
+    UpdateVersion(quote *entity.Quote, version int) error
+    // Delete soft-deletes a quote. A version of 0 skips the version check.
+    Delete(id int, version int) error
     FindByID(id int) (*entity.Quote, error)
+    // FindByIDs returns the quotes with the given IDs, soft-deleted ones
+    // included.
+    FindByIDs(ids []int) ([]entity.Quote, error)
+    // Restore undoes the soft delete of a quote.
+    Restore(id int) error
+    // SyncIDSequence moves the ID sequence past quotes stored with explicit
+    // IDs.
+    SyncIDSequence() error
     FindAll() ([]entity.Quote, error)
+    // List returns one page of quotes matching the filter together with the
+    // cursor for the next page, which is empty on the last page.
+    List(filter QuoteFilter) ([]entity.Quote, string, error)
+    FindByNaturalKeys(keys []string) ([]entity.Quote, error)
+    FindBySource(source string) ([]entity.Quote, error)
+    // FindByBands returns the quotes in one of langs that share a band hash
+    // with bands, the candidates of a near-duplicate check.
+    FindByBands(bands []int64, langs []string) ([]entity.Quote, error)
+    // FindUnbanded returns up to limit quotes stored without band hashes.
+    FindUnbanded(limit int) ([]entity.Quote, error)
+    // SetFingerprints stores the fingerprints and band hashes of quotes
+    // without bumping their versions.
+    SetFingerprints(quotes []entity.Quote) error
+    FindByTranslationGroup(group string) ([]entity.Quote, error)
+    // Search returns one page of quotes matching the search, best first,
+    // together with the cursor for the next page.
+    Search(search QuoteSearch) ([]QuoteSearchHit, string, error)
+    // CountSearch returns how many quotes match the search in total.
+    CountSearch(search QuoteSearch) (int64, error)
+    // TouchImportRun stamps the quotes with the given keys as seen by run.
+    TouchImportRun(keys []string, run string) error
+    // DeleteStale removes the quotes of source not seen by run.
+    DeleteStale(source, run string) (int64, error)
+    // Transaction runs fn against a repository bound to one transaction,
+    // committing when fn returns nil. Nested calls use savepoints.
+    Transaction(fn func(repo QuoteRepository) error) error
 } 
\ No newline at end of file
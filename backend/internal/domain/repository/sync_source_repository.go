@@ -0,0 +1,18 @@
+package repository
+
+import (
+    "backend/internal/domain/entity"
+    "time"
+)
+
+type SyncSourceRepository interface {
+    Store(source *entity.SyncSource) (int, error)
+    // Update returns ErrNotFound when the source has been deleted.
+    Update(source *entity.SyncSource) error
+    Delete(id int) error
+    FindByID(id int) (*entity.SyncSource, error)
+    FindAll() ([]entity.SyncSource, error)
+    // FindDue returns the sources that are not paused and whose next run is
+    // at or before now.
+    FindDue(now time.Time) ([]entity.SyncSource, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import "backend/internal/domain/entity"
+
+// TagSelector picks the quotes and flyers a bulk tag operation touches. Tags
+// matches items carrying any of them, case-insensitively. Zero values are
+// ignored.
+type TagSelector struct {
+    Tags     []string
+    Lang     string
+    QuoteIDs []int
+    FlyerIDs []uint
+}
+
+type TagRepository interface {
+    FindQuotes(selector TagSelector) ([]entity.Quote, error)
+    FindFlyers(selector TagSelector) ([]entity.Flyer, error)
+    // SetQuoteTags replaces a quote's tags and bumps its version.
+    SetQuoteTags(id int, tags []string) error
+    SetFlyerTags(id uint, tags []string) error
+    // Transaction runs fn against a repository bound to one transaction,
+    // committing when fn returns nil.
+    Transaction(fn func(repo TagRepository) error) error
+}
@@ -5,4 +5,6 @@ import "backend/internal/domain/entity"
 type MetadataService interface {
     UpdateImageMetadata(images []entity.Flyer) error
     UpdateQuoteMetadata(quotes []entity.Quote) error
+    // UpdateDailyQuoteMetadata publishes the quote of the day schedule.
+    UpdateDailyQuoteMetadata(days []entity.DailyQuote) error
 } 
\ No newline at end of file
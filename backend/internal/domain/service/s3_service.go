@@ -1,6 +1,58 @@
 package service
 
+import (
+    "errors"
+    "io"
+    "time"
+)
+
+var (
+    // ErrObjectNotFound is returned when the requested object does not exist.
+    ErrObjectNotFound = errors.New("object not found")
+
+    // ErrInvalidRange is returned when a byte range cannot be satisfied.
+    ErrInvalidRange = errors.New("requested range not satisfiable")
+
+    // ErrObjectChanged is returned when an object no longer has the ETag a
+    // read was made conditional on.
+    ErrObjectChanged = errors.New("object changed")
+)
+
+// ObjectInfo describes a stored object without its content.
+type ObjectInfo struct {
+    ContentType   string
+    ContentLength int64
+    ETag          string
+    LastModified  time.Time
+}
+
+// Object is an open stored object. The caller must close Body.
+type Object struct {
+    ObjectInfo
+    // ContentRange is set when only part of the object was requested.
+    ContentRange string
+    Body         io.ReadCloser
+}
+
 type S3Service interface {
     UploadImage(filePath string, fileName string) error
     UploadMetadata(filePath string, fileName string) error
-} 
\ No newline at end of file
+    // ImageKey returns the object key UploadImage stores fileName under.
+    ImageKey(fileName string) string
+    HeadObject(key string) (*ObjectInfo, error)
+    // GetObject opens an object for reading. byteRange is an optional HTTP
+    // Range header value such as "bytes=0-1023". When ifMatch is set the read
+    // fails with ErrObjectChanged unless the object still has that ETag.
+    GetObject(key string, byteRange string, ifMatch string) (*Object, error)
+    PresignGetObject(key string, ttl time.Duration) (string, error)
+    // DeleteObject removes an object. Deleting a missing object succeeds.
+    DeleteObject(key string) error
+}
+
+// URLResolver turns stored object keys into URLs clients can fetch.
+type URLResolver interface {
+    ObjectURL(key string) (string, error)
+    // PublicURL returns a stable URL for published metadata. It is never
+    // presigned, so it neither expires nor changes between publishes.
+    PublicURL(key string) string
+}
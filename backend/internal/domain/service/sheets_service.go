@@ -0,0 +1,9 @@
+package service
+
+// SheetsService reads cell values from Google Sheets.
+type SheetsService interface {
+    // ReadRange returns the rows of readRange (an A1 range or tab name).
+    ReadRange(spreadsheetID, readRange string) ([][]interface{}, error)
+    // SheetTitles lists the tab names of a spreadsheet in display order.
+    SheetTitles(spreadsheetID string) ([]string, error)
+}
@@ -0,0 +1,89 @@
+package googlesheets
+
+import (
+	"backend/internal/config"
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+type SheetsService struct {
+	opts []option.ClientOption
+
+	// svc is created on first use so a server without Sheets credentials
+	// still starts.
+	mu  sync.Mutex
+	svc *sheets.Service
+}
+
+// NewSheetsService authenticates as configured by cfg.SheetsAuth.
+func NewSheetsService(cfg *config.Config) *SheetsService {
+	var opts []option.ClientOption
+	switch cfg.SheetsAuth {
+	case config.SheetsAuthFile:
+		opts = append(opts, option.WithCredentialsFile(cfg.SheetsCredentialsFile))
+	case config.SheetsAuthJSON:
+		opts = append(opts, option.WithCredentialsJSON([]byte(cfg.SheetsCredentialsJSON)))
+	}
+	if cfg.SheetsEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(cfg.SheetsEndpoint))
+	}
+	return NewSheetsServiceWithOptions(opts...)
+}
+
+// NewSheetsServiceWithOptions builds the service from raw client options,
+// for example to talk to a sheetstest.Server.
+func NewSheetsServiceWithOptions(opts ...option.ClientOption) *SheetsService {
+	return &SheetsService{opts: opts}
+}
+
+func (s *SheetsService) ReadRange(spreadsheetID, readRange string) ([][]interface{}, error) {
+	svc, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Spreadsheets.Values.Get(spreadsheetID, readRange).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read data from sheet: %w", err)
+	}
+	return resp.Values, nil
+}
+
+func (s *SheetsService) SheetTitles(spreadsheetID string) ([]string, error) {
+	svc, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Spreadsheets.Get(spreadsheetID).Fields("sheets.properties.title").Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list sheets: %w", err)
+	}
+
+	titles := make([]string, 0, len(resp.Sheets))
+	for _, sheet := range resp.Sheets {
+		if sheet.Properties != nil {
+			titles = append(titles, sheet.Properties.Title)
+		}
+	}
+	return titles, nil
+}
+
+func (s *SheetsService) client() (*sheets.Service, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.svc != nil {
+		return s.svc, nil
+	}
+	svc, err := sheets.NewService(context.Background(), s.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Sheets service: %w", err)
+	}
+	s.svc = svc
+	return svc, nil
+}
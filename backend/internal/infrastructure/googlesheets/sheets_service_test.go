@@ -0,0 +1,44 @@
+package googlesheets
+
+import (
+	"backend/internal/infrastructure/googlesheets/sheetstest"
+	"reflect"
+	"testing"
+)
+
+func TestSheetsServiceAgainstFakeServer(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+	srv.SetTab("doc1", "English", [][]interface{}{
+		{"id", "text", "tags"},
+		{"q1", "Be kind.", "life"},
+		{"q2", "Stay curious.", "mind"},
+	})
+	srv.SetTab("doc1", "It's Hindi", [][]interface{}{{"text"}})
+
+	svc := NewSheetsServiceWithOptions(srv.ClientOptions()...)
+
+	titles, err := svc.SheetTitles("doc1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(titles, []string{"English", "It's Hindi"}) {
+		t.Errorf("unexpected titles: %v", titles)
+	}
+
+	rows, err := svc.ReadRange("doc1", "English!B2:C")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := [][]interface{}{{"Be kind.", "life"}, {"Stay curious.", "mind"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("expected %v, got %v", want, rows)
+	}
+
+	if rows, err := svc.ReadRange("doc1", "'It''s Hindi'"); err != nil || len(rows) != 1 {
+		t.Errorf("expected the quoted tab to be read, got %v, %v", rows, err)
+	}
+	if _, err := svc.ReadRange("missing", "English"); err == nil {
+		t.Error("expected an error for an unknown spreadsheet")
+	}
+}
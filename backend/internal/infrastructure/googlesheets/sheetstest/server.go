@@ -0,0 +1,185 @@
+// Package sheetstest provides an in-memory stand-in for the Google Sheets v4
+// API, so code that reads spreadsheets can be tested offline.
+package sheetstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/option"
+)
+
+// Server answers spreadsheets.get and spreadsheets.values.get for the
+// spreadsheets registered with SetTab.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	spreadsheets map[string]*spreadsheet
+	requests     int
+}
+
+type spreadsheet struct {
+	titles []string
+	tabs   map[string][][]interface{}
+}
+
+// NewServer starts a server. Callers must Close it.
+func NewServer() *Server {
+	s := &Server{spreadsheets: make(map[string]*spreadsheet)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// SetTab creates or replaces a tab. Tabs keep the order they were first set
+// in.
+func (s *Server) SetTab(spreadsheetID, title string, rows [][]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.spreadsheets[spreadsheetID]
+	if !ok {
+		doc = &spreadsheet{tabs: make(map[string][][]interface{})}
+		s.spreadsheets[spreadsheetID] = doc
+	}
+	if _, ok := doc.tabs[title]; !ok {
+		doc.titles = append(doc.titles, title)
+	}
+	doc.tabs[title] = rows
+}
+
+// Requests returns how many API calls the server has answered.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// ClientOptions points a Sheets client at the server without credentials.
+func (s *Server) ClientOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithEndpoint(s.URL + "/"),
+		option.WithHTTPClient(s.Client()),
+		option.WithoutAuthentication(),
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	// /v4/spreadsheets/{id} or /v4/spreadsheets/{id}/values/{range}
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/v4/spreadsheets/"), "/")
+	if r.Method != http.MethodGet || len(parts) == 0 || parts[0] == "" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Not found")
+		return
+	}
+	id, _ := url.PathUnescape(parts[0])
+	doc, ok := s.spreadsheets[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		sheets := make([]map[string]interface{}, len(doc.titles))
+		for i, title := range doc.titles {
+			sheets[i] = map[string]interface{}{"properties": map[string]interface{}{"title": title}}
+		}
+		writeJSON(w, map[string]interface{}{"spreadsheetId": id, "sheets": sheets})
+	case len(parts) == 3 && parts[1] == "values":
+		a1, _ := url.PathUnescape(parts[2])
+		values, err := doc.read(a1)
+		if err != "" {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"range": a1, "majorDimension": "ROWS", "values": values})
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Not found")
+	}
+}
+
+// cellRef matches the cell part of an A1 range: "B2", "B", or "2".
+var cellRef = regexp.MustCompile(`^([A-Za-z]*)(\d*)$`)
+
+// read resolves an A1 range such as "'My tab'!A2:C" against the tabs.
+func (doc *spreadsheet) read(a1 string) ([][]interface{}, string) {
+	title, cells := a1, ""
+	if i := strings.LastIndex(a1, "!"); i >= 0 {
+		title, cells = a1[:i], a1[i+1:]
+	}
+	if len(title) >= 2 && strings.HasPrefix(title, "'") && strings.HasSuffix(title, "'") {
+		title = strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+	rows, ok := doc.tabs[title]
+	if !ok {
+		return nil, "Unable to parse range: " + a1
+	}
+	if cells == "" {
+		return rows, ""
+	}
+
+	from, to, _ := strings.Cut(cells, ":")
+	startCol, startRow, ok1 := parseCell(from, 0, 1)
+	endCol, endRow, ok2 := parseCell(to, -1, -1)
+	if !ok1 || !ok2 {
+		return nil, "Unable to parse range: " + a1
+	}
+	if to == "" {
+		endCol, endRow = startCol, startRow
+	}
+
+	var out [][]interface{}
+	for i := startRow - 1; i < len(rows) && (endRow < 0 || i < endRow); i++ {
+		row := rows[i]
+		var cut []interface{}
+		for j := startCol; j < len(row) && (endCol < 0 || j <= endCol); j++ {
+			cut = append(cut, row[j])
+		}
+		out = append(out, cut)
+	}
+	return out, ""
+}
+
+// parseCell returns the 0-based column and 1-based row of a cell
+// reference, using the defaults for parts that are left out.
+func parseCell(ref string, defCol, defRow int) (int, int, bool) {
+	m := cellRef.FindStringSubmatch(ref)
+	if m == nil {
+		return 0, 0, false
+	}
+	col, row := defCol, defRow
+	if m[1] != "" {
+		col = 0
+		for _, c := range strings.ToUpper(m[1]) {
+			col = col*26 + int(c-'A'+1)
+		}
+		col--
+	}
+	if m[2] != "" {
+		row, _ = strconv.Atoi(m[2])
+	}
+	return col, row, true
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, status, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{"code": code, "message": message, "status": status},
+	})
+}
@@ -2,21 +2,43 @@ package metadata
 
 import (
 	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
 	"backend/internal/domain/service"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// defaultKeepSnapshots is how many versioned snapshots of each catalog are
+// kept when no other number is configured.
+const defaultKeepSnapshots = 10
+
 type metadataService struct {
 	s3Service service.S3Service
+	catalogs  repository.CatalogRepository
+	// keep is how many versioned snapshots of each catalog are kept.
+	keep int
+	now  func() time.Time
 }
 
-func NewMetadataService(s3Service service.S3Service) service.MetadataService {
+func NewMetadataService(s3Service service.S3Service, catalogs repository.CatalogRepository, keep int) service.MetadataService {
+	if keep <= 0 {
+		keep = defaultKeepSnapshots
+	}
 	return &metadataService{
 		s3Service: s3Service,
+		catalogs:  catalogs,
+		keep:      keep,
+		now:       time.Now,
 	}
 }
 
@@ -37,58 +59,336 @@ type QuoteMetadata struct {
 	Metadata Metadata       `json:"metadata"`
 }
 
+type DailyQuoteMetadata struct {
+	Days     []entity.DailyQuote `json:"days"`
+	Metadata Metadata            `json:"metadata"`
+}
+
+// Pointer is the small file, such as quotesMetadata.latest.json, that names
+// the latest snapshot of a catalog and the delta that led to it. Hash is the
+// hex SHA-256 of the snapshot file and Size its length in bytes.
+type Pointer struct {
+	Catalog     string `json:"catalog"`
+	Version     int    `json:"version"`
+	File        string `json:"file"`
+	Hash        string `json:"hash"`
+	Size        int64  `json:"size"`
+	Delta       string `json:"delta,omitempty"`
+	LastUpdated string `json:"lastUpdated"`
+}
+
+// Delta is the file, such as quotesMetadata.v57.delta.json, listing the
+// items a version added, updated and deleted since the version From.
+type Delta struct {
+	Catalog string                 `json:"catalog"`
+	From    int                    `json:"from"`
+	Version int                    `json:"version"`
+	Changes []entity.CatalogChange `json:"changes"`
+}
+
+// catalogFiles says where a catalog is written: the directory and file name
+// environment variables, the default file name and the URL variable.
+type catalogFiles struct {
+	catalog         string
+	pathEnv         string
+	filenameEnv     string
+	defaultFilename string
+	urlEnv          string
+}
+
+var (
+	imageFiles = catalogFiles{entity.CatalogImages, "IMAGE_METADATA_PATH", "IMAGE_METADATA_FILENAME", "imagesMetadata.json", "IMAGE_METADATA_URL"}
+	quoteFiles = catalogFiles{entity.CatalogQuotes, "QUOTE_METADATA_PATH", "QUOTE_METADATA_FILENAME", "quotesMetadata.json", "QUOTE_METADATA_URL"}
+	dailyFiles = catalogFiles{entity.CatalogDailyQuotes, "QUOTE_METADATA_PATH", "DAILY_QUOTE_METADATA_FILENAME", "dailyQuotes.json", "DAILY_QUOTE_METADATA_URL"}
+)
+
 func (s *metadataService) UpdateImageMetadata(images []entity.Flyer) error {
-	metadata := Metadata{
-		Version:     "1",
-		LastUpdated: time.Now().Format(time.RFC3339),
-		Total:       len(images),
-		Url:         os.Getenv("IMAGE_METADATA_URL"),
+	items := make([]catalogItem, len(images))
+	for i := range images {
+		items[i] = catalogItem{id: strconv.FormatUint(uint64(images[i].Id), 10), value: images[i]}
 	}
+	return s.publish(imageFiles, items, func(meta Metadata) interface{} {
+		return ImageMetadata{Images: images, Metadata: meta}
+	})
+}
 
-	imageData := ImageMetadata{
-		Images:   images,
-		Metadata: metadata,
+func (s *metadataService) UpdateQuoteMetadata(quotes []entity.Quote) error {
+	items := make([]catalogItem, len(quotes))
+	for i := range quotes {
+		items[i] = catalogItem{id: strconv.Itoa(quotes[i].Id), value: quotes[i]}
 	}
+	return s.publish(quoteFiles, items, func(meta Metadata) interface{} {
+		return QuoteMetadata{Quotes: quotes, Metadata: meta}
+	})
+}
 
-	return s.saveAndUploadMetadata(imageData, "IMAGE_METADATA_PATH", "IMAGE_METADATA_FILENAME", "imagesMetadata.json")
+func (s *metadataService) UpdateDailyQuoteMetadata(days []entity.DailyQuote) error {
+	items := make([]catalogItem, len(days))
+	for i := range days {
+		items[i] = catalogItem{id: strconv.Itoa(days[i].Id), value: days[i]}
+	}
+	return s.publish(dailyFiles, items, func(meta Metadata) interface{} {
+		return DailyQuoteMetadata{Days: days, Metadata: meta}
+	})
 }
 
-func (s *metadataService) UpdateQuoteMetadata(quotes []entity.Quote) error {
-	metadata := Metadata{
-		Version:     "1",
-		LastUpdated: time.Now().Format(time.RFC3339),
-		Total:       len(quotes),
-		Url:         os.Getenv("QUOTE_METADATA_URL"),
+// catalogItem is one item of a catalog and the ID the change log tracks it
+// by.
+type catalogItem struct {
+	id    string
+	value interface{}
+}
+
+// publish writes a new version of a catalog unless its items are unchanged
+// since the latest snapshot. Publishes of one catalog are serialized under
+// its lock: the items are diffed against the latest snapshot and only then
+// take the next version, which is recorded with its changes and committed
+// before any file is uploaded, so a version number is never handed out
+// twice and its files are never overwritten. Snapshots beyond the newest
+// keep are removed afterwards, with their deltas.
+func (s *metadataService) publish(files catalogFiles, items []catalogItem, document func(Metadata) interface{}) error {
+	raw := make([]json.RawMessage, len(items))
+	hashes := make(map[string]string, len(items))
+	for i, item := range items {
+		data, err := json.Marshal(item.value)
+		if err != nil {
+			return fmt.Errorf("unable to convert to JSON: %v", err)
+		}
+		raw[i] = data
+		hashes[item.id] = sha256Hex(data)
+	}
+	content, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("unable to convert to JSON: %v", err)
 	}
+	contentHash := sha256Hex(content)
+
+	var snapshot *entity.CatalogSnapshot
+	var data, delta []byte
+	err = s.catalogs.Transaction(func(repo repository.CatalogRepository) error {
+		if err := repo.Lock(files.catalog); err != nil {
+			return fmt.Errorf("failed to lock the %s catalog: %w", files.catalog, err)
+		}
+		latest, err := repo.LatestSnapshot(files.catalog)
+		switch {
+		case err == nil && latest.ContentHash == contentHash && latest.Current:
+			return nil
+		case errors.Is(err, repository.ErrNotFound):
+			latest = nil
+		case err != nil:
+			return fmt.Errorf("failed to load the latest %s snapshot: %w", files.catalog, err)
+		}
+
+		// without item hashes to diff against, as for the first version, no
+		// delta is published and clients behind this version resync
+		var changes []entity.CatalogChange
+		if latest != nil && latest.ItemHashes != nil {
+			changes = diffItems(files.catalog, latest.ItemHashes, items, raw, hashes)
+		}
+
+		version, err := repo.NextVersion(files.catalog)
+		if err != nil {
+			return fmt.Errorf("failed to bump the %s version: %w", files.catalog, err)
+		}
+		for i := range changes {
+			changes[i].Version = version
+		}
+		publishedAt := s.now().UTC()
+		data, err = json.MarshalIndent(document(Metadata{
+			Version:     strconv.Itoa(version),
+			LastUpdated: publishedAt.Format(time.RFC3339),
+			Total:       len(items),
+			Url:         os.Getenv(files.urlEnv),
+		}), "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to convert to JSON: %v", err)
+		}
 
-	quoteData := QuoteMetadata{
-		Quotes:   quotes,
-		Metadata: metadata,
+		snapshot = &entity.CatalogSnapshot{
+			Catalog:     files.catalog,
+			Version:     version,
+			File:        files.snapshotName(version),
+			Hash:        sha256Hex(data),
+			Size:        int64(len(data)),
+			ContentHash: contentHash,
+			ItemHashes:  hashes,
+			PublishedAt: publishedAt,
+		}
+		if changes != nil {
+			delta, err = json.MarshalIndent(Delta{
+				Catalog: files.catalog,
+				From:    latest.Version,
+				Version: version,
+				Changes: changes,
+			}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("unable to convert to JSON: %v", err)
+			}
+			snapshot.Delta = files.deltaName(version)
+		}
+
+		if err := repo.StoreSnapshot(snapshot, changes); err != nil {
+			return fmt.Errorf("failed to record %s snapshot %d: %w", files.catalog, version, err)
+		}
+		return nil
+	})
+	if err != nil || snapshot == nil {
+		return err
 	}
 
-	return s.saveAndUploadMetadata(quoteData, "QUOTE_METADATA_PATH", "QUOTE_METADATA_FILENAME", "quotesMetadata.json")
+	dir := os.Getenv(files.pathEnv)
+	if err := s.upload(files, dir, snapshot, data, delta); err != nil {
+		return err
+	}
+	s.prune(files.catalog, dir)
+	return nil
 }
 
-func (s *metadataService) saveAndUploadMetadata(data interface{}, pathEnv, filenameEnv, defaultFilename string) error {
-	metadataPath := os.Getenv(pathEnv)
-	metadataFileName := os.Getenv(filenameEnv)
-	if metadataFileName == "" {
-		metadataFileName = defaultFilename
+// upload writes the files of a committed snapshot under the catalog lock:
+// the delta and the immutable snapshot first, then the unversioned file
+// older clients read, and the pointer last, so the pointer never names a
+// file that is not there yet. When a newer version was committed meanwhile
+// the pointer is left to it. A snapshot whose upload fails is never marked
+// current, so the next publish supersedes it even if nothing changed.
+func (s *metadataService) upload(files catalogFiles, dir string, snapshot *entity.CatalogSnapshot, data, delta []byte) error {
+	return s.catalogs.Transaction(func(repo repository.CatalogRepository) error {
+		if err := repo.Lock(files.catalog); err != nil {
+			return fmt.Errorf("failed to lock the %s catalog: %w", files.catalog, err)
+		}
+		if delta != nil {
+			if err := s.saveAndUpload(dir, snapshot.Delta, delta); err != nil {
+				return err
+			}
+		}
+		if err := s.saveAndUpload(dir, snapshot.File, data); err != nil {
+			return err
+		}
+
+		latest, err := repo.LatestSnapshot(files.catalog)
+		if err != nil {
+			return fmt.Errorf("failed to load the latest %s snapshot: %w", files.catalog, err)
+		}
+		if latest.Version != snapshot.Version {
+			return nil
+		}
+		if err := s.saveAndUpload(dir, files.filename(), data); err != nil {
+			return err
+		}
+		pointer, err := json.MarshalIndent(Pointer{
+			Catalog:     snapshot.Catalog,
+			Version:     snapshot.Version,
+			File:        snapshot.File,
+			Hash:        snapshot.Hash,
+			Size:        snapshot.Size,
+			Delta:       snapshot.Delta,
+			LastUpdated: snapshot.PublishedAt.Format(time.RFC3339),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to convert to JSON: %v", err)
+		}
+		if err := s.saveAndUpload(dir, files.pointerName(), pointer); err != nil {
+			return err
+		}
+		return repo.MarkCurrent(files.catalog, snapshot.Version)
+	})
+}
+
+// diffItems lists the items added or updated since the previous version, in
+// catalog order, followed by the deleted ones by ID. The caller sets the
+// version once it is taken.
+func diffItems(catalog string, previous map[string]string, items []catalogItem, raw []json.RawMessage, hashes map[string]string) []entity.CatalogChange {
+	changes := []entity.CatalogChange{}
+	for i, item := range items {
+		op := entity.ChangeUpdate
+		switch old, ok := previous[item.id]; {
+		case !ok:
+			op = entity.ChangeAdd
+		case old == hashes[item.id]:
+			continue
+		}
+		changes = append(changes, entity.CatalogChange{Catalog: catalog, ItemId: item.id, Op: op, Item: raw[i]})
 	}
 
-	if err := os.MkdirAll(metadataPath, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+	var deleted []string
+	for id := range previous {
+		if _, ok := hashes[id]; !ok {
+			deleted = append(deleted, id)
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool { return lessID(deleted[i], deleted[j]) })
+	for _, id := range deleted {
+		changes = append(changes, entity.CatalogChange{Catalog: catalog, ItemId: id, Op: entity.ChangeDelete})
+	}
+	return changes
+}
+
+// lessID orders numeric IDs by value and anything else as text.
+func lessID(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
 	}
+	return a < b
+}
 
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// prune removes the snapshots beyond the newest keep. Failures are only
+// logged: the new version is already published.
+func (s *metadataService) prune(catalog, dir string) {
+	pruned, err := s.catalogs.PruneSnapshots(catalog, s.keep)
 	if err != nil {
-		return fmt.Errorf("unable to convert to JSON: %v", err)
+		log.Printf("Failed to prune %s snapshots: %v", catalog, err)
+		return
+	}
+	for _, old := range pruned {
+		for _, name := range []string{old.File, old.Delta} {
+			if name == "" {
+				continue
+			}
+			if err := s.s3Service.DeleteObject(name); err != nil {
+				log.Printf("Failed to delete %s: %v", name, err)
+			}
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed to delete %s: %v", name, err)
+			}
+		}
+	}
+}
+
+func (s *metadataService) saveAndUpload(metadataPath, metadataFileName string, data []byte) error {
+	if err := os.MkdirAll(metadataPath, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	filePath := filepath.Join(metadataPath, metadataFileName)
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("unable to write JSON file: %v", err)
 	}
 
 	return s.s3Service.UploadMetadata(filePath, metadataFileName)
-} 
\ No newline at end of file
+}
+
+func (f catalogFiles) filename() string {
+	if name := os.Getenv(f.filenameEnv); name != "" {
+		return name
+	}
+	return f.defaultFilename
+}
+
+// snapshotName turns quotesMetadata.json into quotesMetadata.v57.json.
+func (f catalogFiles) snapshotName(version int) string {
+	return fmt.Sprintf("%s.v%d.json", strings.TrimSuffix(f.filename(), ".json"), version)
+}
+
+// deltaName turns quotesMetadata.json into quotesMetadata.v57.delta.json.
+func (f catalogFiles) deltaName(version int) string {
+	return fmt.Sprintf("%s.v%d.delta.json", strings.TrimSuffix(f.filename(), ".json"), version)
+}
+
+// pointerName turns quotesMetadata.json into quotesMetadata.latest.json.
+func (f catalogFiles) pointerName() string {
+	return strings.TrimSuffix(f.filename(), ".json") + ".latest.json"
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
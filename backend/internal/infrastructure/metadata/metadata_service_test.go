@@ -0,0 +1,367 @@
+package metadata
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/domain/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeS3 struct {
+	service.S3Service
+	uploads []string
+	deleted []string
+	// failOn makes uploads of that file fail.
+	failOn string
+}
+
+func (s *fakeS3) UploadMetadata(filePath, fileName string) error {
+	if fileName == s.failOn {
+		return errors.New("upload failed")
+	}
+	s.uploads = append(s.uploads, fileName)
+	return nil
+}
+
+func (s *fakeS3) DeleteObject(key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+type fakeCatalogs struct {
+	mu        sync.Mutex
+	locked    map[string]bool
+	versions  map[string]int
+	snapshots []entity.CatalogSnapshot
+	changes   []entity.CatalogChange
+}
+
+// Transaction runs fn holding the lock of every catalog fn locks until it
+// returns, like a transaction-scoped advisory lock.
+func (c *fakeCatalogs) Transaction(fn func(repository.CatalogRepository) error) error {
+	tx := &fakeCatalogTx{fakeCatalogs: c}
+	defer tx.unlock()
+	return fn(tx)
+}
+
+func (c *fakeCatalogs) ReadOnly(fn func(repository.CatalogRepository) error) error {
+	return fn(c)
+}
+
+func (c *fakeCatalogs) Lock(catalog string) error {
+	return errors.New("lock outside a transaction")
+}
+
+func (c *fakeCatalogs) NextVersion(catalog string) (int, error) {
+	if !c.locked[catalog] {
+		return 0, errors.New("version taken without the catalog lock")
+	}
+	c.versions[catalog]++
+	return c.versions[catalog], nil
+}
+
+type fakeCatalogTx struct {
+	*fakeCatalogs
+	held bool
+}
+
+func (tx *fakeCatalogTx) Lock(catalog string) error {
+	tx.mu.Lock()
+	tx.held = true
+	if tx.locked == nil {
+		tx.locked = map[string]bool{}
+	}
+	tx.locked[catalog] = true
+	return nil
+}
+
+func (tx *fakeCatalogTx) unlock() {
+	if tx.held {
+		tx.locked = map[string]bool{}
+		tx.mu.Unlock()
+	}
+}
+
+func (c *fakeCatalogs) StoreSnapshot(s *entity.CatalogSnapshot, changes []entity.CatalogChange) error {
+	c.snapshots = append(c.snapshots, *s)
+	c.changes = append(c.changes, changes...)
+	return nil
+}
+
+func (c *fakeCatalogs) MarkCurrent(catalog string, version int) error {
+	for i := range c.snapshots {
+		if c.snapshots[i].Catalog == catalog && c.snapshots[i].Version == version {
+			c.snapshots[i].Current = true
+		}
+	}
+	return nil
+}
+
+func (c *fakeCatalogs) ListSnapshots(catalog string) ([]entity.CatalogSnapshot, error) {
+	var found []entity.CatalogSnapshot
+	for _, s := range c.snapshots {
+		if s.Catalog == catalog {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func (c *fakeCatalogs) FindChanges(catalog string, since int) ([]entity.CatalogChange, error) {
+	var found []entity.CatalogChange
+	for _, ch := range c.changes {
+		if ch.Catalog == catalog && ch.Version > since {
+			found = append(found, ch)
+		}
+	}
+	return found, nil
+}
+
+func (c *fakeCatalogs) LatestSnapshot(catalog string) (*entity.CatalogSnapshot, error) {
+	for i := len(c.snapshots) - 1; i >= 0; i-- {
+		if c.snapshots[i].Catalog == catalog {
+			s := c.snapshots[i]
+			return &s, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// PruneSnapshots runs outside the publish transaction, so it takes the lock
+// itself to guard the fake's slices.
+func (c *fakeCatalogs) PruneSnapshots(catalog string, keep int) ([]entity.CatalogSnapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sort.SliceStable(c.snapshots, func(i, j int) bool { return c.snapshots[i].Version > c.snapshots[j].Version })
+	var kept, pruned []entity.CatalogSnapshot
+	n := 0
+	for _, s := range c.snapshots {
+		if s.Catalog == catalog {
+			n++
+			if n > keep {
+				pruned = append(pruned, s)
+				continue
+			}
+		}
+		kept = append(kept, s)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Version < kept[j].Version })
+	c.snapshots = kept
+	return pruned, nil
+}
+
+func TestPublishVersionsSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("QUOTE_METADATA_PATH", dir)
+	t.Setenv("QUOTE_METADATA_FILENAME", "")
+
+	s3, catalogs := &fakeS3{}, &fakeCatalogs{versions: map[string]int{entity.CatalogQuotes: 56}}
+	svc := NewMetadataService(s3, catalogs, 2).(*metadataService)
+	svc.now = func() time.Time { return time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC) }
+
+	quotes := []entity.Quote{{Id: 1, Text: "Be kind.", Lang: "en-US"}}
+	if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{"quotesMetadata.v57.json", "quotesMetadata.json", "quotesMetadata.latest.json"}
+	if len(s3.uploads) != 3 || s3.uploads[0] != want[0] || s3.uploads[1] != want[1] || s3.uploads[2] != want[2] {
+		t.Fatalf("expected uploads %v, got %v", want, s3.uploads)
+	}
+
+	var doc QuoteMetadata
+	readJSON(t, filepath.Join(dir, "quotesMetadata.v57.json"), &doc)
+	if doc.Metadata.Version != "57" || doc.Metadata.LastUpdated != "2024-05-01T09:00:00Z" {
+		t.Errorf("unexpected snapshot metadata: %+v", doc.Metadata)
+	}
+	var pointer Pointer
+	readJSON(t, filepath.Join(dir, "quotesMetadata.latest.json"), &pointer)
+	data, _ := os.ReadFile(filepath.Join(dir, "quotesMetadata.v57.json"))
+	if pointer.Version != 57 || pointer.File != "quotesMetadata.v57.json" || pointer.Size != int64(len(data)) || pointer.Hash != sha256Hex(data) {
+		t.Errorf("unexpected pointer: %+v", pointer)
+	}
+
+	// unchanged items take no new version
+	if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+		t.Fatal(err)
+	}
+	if len(s3.uploads) != 3 {
+		t.Errorf("expected an unchanged catalog not to be republished, got %v", s3.uploads)
+	}
+
+	for _, text := range []string{"One.", "Two."} {
+		quotes = append(quotes, entity.Quote{Id: len(quotes) + 1, Text: text})
+		if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s3.deleted) != 1 || s3.deleted[0] != "quotesMetadata.v57.json" {
+		t.Errorf("expected the oldest snapshot to be pruned, got %v", s3.deleted)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "quotesMetadata.v57.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the pruned snapshot file to be removed, got %v", err)
+	}
+	readJSON(t, filepath.Join(dir, "quotesMetadata.latest.json"), &pointer)
+	if pointer.Version != 59 {
+		t.Errorf("expected the pointer to name version 59, got %d", pointer.Version)
+	}
+}
+
+func TestPublishRecordsChanges(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("QUOTE_METADATA_PATH", dir)
+	t.Setenv("QUOTE_METADATA_FILENAME", "")
+
+	s3, catalogs := &fakeS3{}, &fakeCatalogs{versions: map[string]int{}}
+	svc := NewMetadataService(s3, catalogs, 10)
+
+	quotes := []entity.Quote{{Id: 1, Text: "One."}, {Id: 2, Text: "Two."}, {Id: 10, Text: "Ten."}}
+	if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+		t.Fatal(err)
+	}
+	if len(catalogs.changes) != 0 || catalogs.snapshots[0].Delta != "" {
+		t.Errorf("expected the first version to publish no delta, got %+v", catalogs.changes)
+	}
+
+	quotes = []entity.Quote{{Id: 2, Text: "Two, edited."}, {Id: 10, Text: "Ten."}, {Id: 11, Text: "Eleven."}}
+	if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range catalogs.changes {
+		got = append(got, c.Op+":"+c.ItemId)
+		if c.Version != 2 {
+			t.Errorf("expected changes of version 2, got %+v", c)
+		}
+	}
+	if want := "update:2 add:11 delete:1"; fmt.Sprint(got) != "["+want+"]" {
+		t.Errorf("expected changes %s, got %v", want, got)
+	}
+
+	var delta Delta
+	readJSON(t, filepath.Join(dir, "quotesMetadata.v2.delta.json"), &delta)
+	if delta.From != 1 || delta.Version != 2 || len(delta.Changes) != 3 {
+		t.Errorf("unexpected delta file: %+v", delta)
+	}
+	var item entity.Quote
+	if err := json.Unmarshal(delta.Changes[0].Item, &item); err != nil || item.Text != "Two, edited." {
+		t.Errorf("expected the delta to carry the updated quote, got %s", delta.Changes[0].Item)
+	}
+	var pointer Pointer
+	readJSON(t, filepath.Join(dir, "quotesMetadata.latest.json"), &pointer)
+	if pointer.Delta != "quotesMetadata.v2.delta.json" {
+		t.Errorf("expected the pointer to name the delta, got %+v", pointer)
+	}
+}
+
+func TestPublishSerializesVersions(t *testing.T) {
+	t.Setenv("QUOTE_METADATA_PATH", t.TempDir())
+	t.Setenv("QUOTE_METADATA_FILENAME", "")
+
+	catalogs := &fakeCatalogs{versions: map[string]int{}}
+	svc := NewMetadataService(&lockedS3{}, catalogs, 100)
+	if err := svc.UpdateQuoteMetadata([]entity.Quote{{Id: 1, Text: "Base."}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for n := 2; n <= 9; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			quotes := []entity.Quote{{Id: 1, Text: "Base."}, {Id: n, Text: fmt.Sprint("Quote ", n)}}
+			if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+				t.Error(err)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	// every delta starts from the version before it, so replaying the
+	// deltas always lands on the latest snapshot's items
+	items := map[string]bool{"1": true}
+	for i, s := range catalogs.snapshots {
+		if s.Version != i+1 {
+			t.Fatalf("expected consecutive versions, got %d at %d", s.Version, i)
+		}
+		for _, c := range catalogs.changes {
+			if c.Version == s.Version {
+				items[c.ItemId] = c.Op != entity.ChangeDelete
+			}
+		}
+		for id := range s.ItemHashes {
+			if !items[id] {
+				t.Errorf("version %d holds item %s that its deltas never added", s.Version, id)
+			}
+		}
+		for id, present := range items {
+			if _, ok := s.ItemHashes[id]; present && !ok {
+				t.Errorf("version %d lost item %s without a delete", s.Version, id)
+			}
+		}
+	}
+}
+
+func TestPublishUploadsAfterCommit(t *testing.T) {
+	t.Setenv("QUOTE_METADATA_PATH", t.TempDir())
+	t.Setenv("QUOTE_METADATA_FILENAME", "")
+
+	s3, catalogs := &fakeS3{failOn: "quotesMetadata.latest.json"}, &fakeCatalogs{versions: map[string]int{}}
+	svc := NewMetadataService(s3, catalogs, 10)
+	quotes := []entity.Quote{{Id: 1, Text: "Be kind."}}
+	if err := svc.UpdateQuoteMetadata(quotes); err == nil {
+		t.Fatal("expected the failed pointer upload to be reported")
+	}
+	if len(catalogs.snapshots) != 1 || catalogs.snapshots[0].Current {
+		t.Fatalf("expected version 1 to be committed but not current, got %+v", catalogs.snapshots)
+	}
+
+	// the retry takes a new version instead of rewriting the files of 1
+	s3.failOn = ""
+	if err := svc.UpdateQuoteMetadata(quotes); err != nil {
+		t.Fatal(err)
+	}
+	want := "[quotesMetadata.v1.json quotesMetadata.json quotesMetadata.v2.delta.json quotesMetadata.v2.json quotesMetadata.json quotesMetadata.latest.json]"
+	if fmt.Sprint(s3.uploads) != want {
+		t.Errorf("expected uploads %s, got %v", want, s3.uploads)
+	}
+	if latest := catalogs.snapshots[len(catalogs.snapshots)-1]; latest.Version != 2 || !latest.Current {
+		t.Errorf("expected version 2 to be current, got %+v", latest)
+	}
+}
+
+// lockedS3 is a fakeS3 safe for concurrent publishes.
+type lockedS3 struct {
+	mu sync.Mutex
+	fakeS3
+}
+
+func (s *lockedS3) UploadMetadata(filePath, fileName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fakeS3.UploadMetadata(filePath, fileName)
+}
+
+func (s *lockedS3) DeleteObject(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fakeS3.DeleteObject(key)
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
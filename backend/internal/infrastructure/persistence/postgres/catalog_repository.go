@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CatalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) *CatalogRepository {
+	return &CatalogRepository{db: db}
+}
+
+func (r *CatalogRepository) Transaction(fn func(repo repository.CatalogRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&CatalogRepository{db: tx})
+	})
+}
+
+func (r *CatalogRepository) ReadOnly(fn func(repo repository.CatalogRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&CatalogRepository{db: tx})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+// Lock takes a transaction-scoped advisory lock keyed by the catalog name,
+// which also serializes publishers running in other processes.
+func (r *CatalogRepository) Lock(catalog string) error {
+	return r.db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "catalog:"+catalog).Error
+}
+
+func (r *CatalogRepository) NextVersion(catalog string) (int, error) {
+	var version int
+	err := r.db.Raw(`INSERT INTO catalog_versions (catalog, version) VALUES (?, 1)
+		ON CONFLICT (catalog) DO UPDATE SET version = catalog_versions.version + 1
+		RETURNING version`, catalog).Scan(&version).Error
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func (r *CatalogRepository) StoreSnapshot(snapshot *entity.CatalogSnapshot, changes []entity.CatalogChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(changes, 500).Error
+	})
+}
+
+func (r *CatalogRepository) MarkCurrent(catalog string, version int) error {
+	return r.db.Model(&entity.CatalogSnapshot{}).
+		Where("catalog = ? AND version = ?", catalog, version).
+		Update("current", true).Error
+}
+
+func (r *CatalogRepository) LatestSnapshot(catalog string) (*entity.CatalogSnapshot, error) {
+	var snapshot entity.CatalogSnapshot
+	err := r.db.Where("catalog = ?", catalog).Order("version DESC").First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *CatalogRepository) ListSnapshots(catalog string) ([]entity.CatalogSnapshot, error) {
+	var snapshots []entity.CatalogSnapshot
+	if err := r.db.Where("catalog = ?", catalog).Order("version ASC").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (r *CatalogRepository) PruneSnapshots(catalog string, keep int) ([]entity.CatalogSnapshot, error) {
+	var pruned []entity.CatalogSnapshot
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("catalog = ?", catalog).Order("version DESC").Offset(keep).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&pruned).Error
+		if err != nil || len(pruned) == 0 {
+			return err
+		}
+		ids := make([]int, len(pruned))
+		for i, s := range pruned {
+			ids[i] = s.Id
+		}
+		if err := tx.Delete(&entity.CatalogSnapshot{}, ids).Error; err != nil {
+			return err
+		}
+		// deltas are only served from kept versions on, so the changes up to
+		// the oldest kept snapshot are no longer needed
+		var oldest int
+		err = tx.Model(&entity.CatalogSnapshot{}).Where("catalog = ?", catalog).
+			Select("COALESCE(MIN(version), 0)").Scan(&oldest).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("catalog = ? AND version <= ?", catalog, oldest).
+			Delete(&entity.CatalogChange{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+func (r *CatalogRepository) FindChanges(catalog string, since int) ([]entity.CatalogChange, error) {
+	var changes []entity.CatalogChange
+	err := r.db.Where("catalog = ? AND version > ?", catalog, since).
+		Order("version ASC, id ASC").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
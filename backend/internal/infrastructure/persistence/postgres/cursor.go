@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"backend/internal/domain/repository"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// cursor marks the last row of a page for keyset pagination. The sort key
+// and direction are embedded so a cursor cannot be replayed against a
+// different ordering.
+type cursor struct {
+	Sort  string          `json:"s"`
+	Desc  bool            `json:"d,omitempty"`
+	Value json.RawMessage `json:"v,omitempty"`
+	ID    int64           `json:"id"`
+}
+
+func encodeCursor(c cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor parses an opaque cursor and checks it was issued for the
+// given sort order.
+func decodeCursor(s, sort string, desc bool) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, repository.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, repository.ErrInvalidCursor
+	}
+	if c.Sort != sort || c.Desc != desc {
+		return c, repository.ErrInvalidCursor
+	}
+	return c, nil
+}
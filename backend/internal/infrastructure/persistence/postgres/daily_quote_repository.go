@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type DailyQuoteRepository struct {
+	db *gorm.DB
+}
+
+func NewDailyQuoteRepository(db *gorm.DB) *DailyQuoteRepository {
+	return &DailyQuoteRepository{db: db}
+}
+
+func (r *DailyQuoteRepository) Store(day *entity.DailyQuote) (int, error) {
+	if err := r.db.Create(day).Error; err != nil {
+		return 0, err
+	}
+	return day.Id, nil
+}
+
+func (r *DailyQuoteRepository) Update(day *entity.DailyQuote) error {
+	return r.db.Save(day).Error
+}
+
+func (r *DailyQuoteRepository) Delete(id int) error {
+	result := r.db.Delete(&entity.DailyQuote{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DailyQuoteRepository) FindByID(id int) (*entity.DailyQuote, error) {
+	var day entity.DailyQuote
+	if err := r.db.First(&day, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &day, nil
+}
+
+func (r *DailyQuoteRepository) FindRange(from, to, locale string) ([]entity.DailyQuote, error) {
+	query := r.db.Where("date >= ? AND date <= ?", from, to)
+	if locale != "" {
+		query = query.Where("LOWER(locale) = LOWER(?)", locale)
+	}
+	var days []entity.DailyQuote
+	if err := query.Order("date, locale").Find(&days).Error; err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func (r *DailyQuoteRepository) UsedQuoteIDs(locale string) ([]int, error) {
+	var ids []int
+	err := r.db.Model(&entity.DailyQuote{}).
+		Where("LOWER(locale) = LOWER(?)", locale).
+		Distinct().Pluck("quote_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -16,9 +16,38 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	// Auto-migrate entities
-	if err := db.AutoMigrate(&entity.Flyer{}, &entity.Quote{}); err != nil {
+	if err := db.AutoMigrate(&entity.Flyer{}, &entity.Quote{}, &entity.ImportProfile{}, &entity.SyncSource{}, &entity.DailyQuote{}, &entity.CatalogVersion{}, &entity.CatalogSnapshot{}, &entity.CatalogChange{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := createIndexes(db); err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	if err := createSearchColumn(db); err != nil {
+		return nil, fmt.Errorf("failed to create quote search column: %w", err)
+	}
+
 	return db, nil
 }
+
+// indexes that AutoMigrate cannot express through struct tags
+var indexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_flyers_tags ON flyers USING GIN ((tags::jsonb))`,
+	// quotes created before natural keys existed have none, and soft-deleted
+	// quotes must not block re-importing the same text
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_quotes_natural_key_live ON quotes (natural_key) WHERE natural_key <> '' AND deleted_at IS NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_quotes_author ON quotes (LOWER(author))`,
+	`CREATE INDEX IF NOT EXISTS idx_quotes_bands ON quotes USING GIN (bands)`,
+	// backs the NULL-safe created_at ordering of flyer listings
+	`CREATE INDEX IF NOT EXISTS idx_flyers_created_at_sort ON flyers ((COALESCE(created_at, '0001-01-01 00:00:00+00')), id)`,
+}
+
+func createIndexes(db *gorm.DB) error {
+	for _, stmt := range indexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,9 +2,27 @@ package postgres
 
 import (
     "backend/internal/domain/entity"
+    "backend/internal/domain/repository"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
     "gorm.io/gorm"
 )
 
+// imageSortColumns maps the public sort keys to flyer columns. Flyers stored
+// before created_at was filled in sort as the zero time, which is also what
+// their cursor carries, so keyset pages never skip them.
+var imageSortColumns = map[string]string{
+    repository.ImageSortID:        "id",
+    repository.ImageSortCreatedAt: createdAtSortKey,
+    repository.ImageSortWidth:     "width",
+    repository.ImageSortHeight:    "height",
+}
+
+const createdAtSortKey = "COALESCE(created_at, '0001-01-01 00:00:00+00')"
+
 type ImageRepository struct {
     db *gorm.DB
 }
@@ -28,6 +46,9 @@ func (r *ImageRepository) Update(image *entity.Flyer) error {
 func (r *ImageRepository) FindByID(id uint) (*entity.Flyer, error) {
     var image entity.Flyer
     if err := r.db.First(&image, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, repository.ErrNotFound
+        }
         return nil, err
     }
     return &image, nil
@@ -39,4 +60,138 @@ func (r *ImageRepository) FindAll() ([]entity.Flyer, error) {
         return nil, err
     }
     return images, nil
-} 
\ No newline at end of file
+}
+
+func (r *ImageRepository) FindByFileNames(names []string) ([]entity.Flyer, error) {
+    var images []entity.Flyer
+    if len(names) == 0 {
+        return images, nil
+    }
+    if err := r.db.Where("file_name IN ?", names).Order("id").Find(&images).Error; err != nil {
+        return nil, err
+    }
+    return images, nil
+}
+
+func (r *ImageRepository) List(filter repository.ImageFilter) ([]entity.Flyer, string, error) {
+    sort := filter.Sort
+    if sort == "" {
+        sort = repository.ImageSortID
+    }
+    column, ok := imageSortColumns[sort]
+    if !ok {
+        return nil, "", fmt.Errorf("unsupported sort field %q", sort)
+    }
+
+    op, dir := ">", "ASC"
+    if filter.Desc {
+        op, dir = "<", "DESC"
+    }
+
+    query := applyImageFilter(r.db.Model(&entity.Flyer{}), filter)
+    if filter.Cursor != "" {
+        c, err := decodeCursor(filter.Cursor, sort, filter.Desc)
+        if err != nil {
+            return nil, "", err
+        }
+        if column == "id" {
+            query = query.Where("id "+op+" ?", c.ID)
+        } else {
+            value, err := imageCursorValue(sort, c.Value)
+            if err != nil {
+                return nil, "", repository.ErrInvalidCursor
+            }
+            query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, op), value, c.ID)
+        }
+    }
+    if column != "id" {
+        query = query.Order(column + " " + dir)
+    }
+    query = query.Order("id " + dir)
+
+    limit := filter.Limit
+    if limit <= 0 {
+        limit = repository.DefaultImagePageSize
+    }
+
+    var images []entity.Flyer
+    if err := query.Limit(limit + 1).Find(&images).Error; err != nil {
+        return nil, "", err
+    }
+    if len(images) <= limit {
+        return images, "", nil
+    }
+
+    images = images[:limit]
+    last := images[len(images)-1]
+    value, err := json.Marshal(imageSortValue(sort, &last))
+    if err != nil {
+        return nil, "", err
+    }
+    next, err := encodeCursor(cursor{Sort: sort, Desc: filter.Desc, Value: value, ID: int64(last.Id)})
+    if err != nil {
+        return nil, "", err
+    }
+    return images, next, nil
+}
+
+func applyImageFilter(query *gorm.DB, filter repository.ImageFilter) *gorm.DB {
+    if filter.Tag != "" {
+        tag, _ := json.Marshal([]string{filter.Tag})
+        query = query.Where("tags::jsonb @> ?::jsonb", string(tag))
+    }
+    if filter.Lang != "" {
+        query = query.Where("lang = ?", filter.Lang)
+    }
+    if filter.Orientation != "" {
+        query = query.Where("orientation = ?", filter.Orientation)
+    }
+    if filter.FileFormat != "" {
+        query = query.Where("UPPER(file_format) = UPPER(?)", filter.FileFormat)
+    }
+    if filter.TemplateId != "" {
+        query = query.Where("template_id = ?", filter.TemplateId)
+    }
+    if filter.MinWidth > 0 {
+        query = query.Where("width >= ?", filter.MinWidth)
+    }
+    if filter.MaxWidth > 0 {
+        query = query.Where("width <= ?", filter.MaxWidth)
+    }
+    if filter.MinHeight > 0 {
+        query = query.Where("height >= ?", filter.MinHeight)
+    }
+    if filter.MaxHeight > 0 {
+        query = query.Where("height <= ?", filter.MaxHeight)
+    }
+    if filter.CreatedAfter != nil {
+        query = query.Where("created_at >= ?", *filter.CreatedAfter)
+    }
+    if filter.CreatedBefore != nil {
+        query = query.Where("created_at < ?", *filter.CreatedBefore)
+    }
+    return query
+}
+
+func imageSortValue(sort string, image *entity.Flyer) interface{} {
+    switch sort {
+    case repository.ImageSortCreatedAt:
+        return image.CreatedAt
+    case repository.ImageSortWidth:
+        return image.Design.Resolution.Width
+    case repository.ImageSortHeight:
+        return image.Design.Resolution.Height
+    }
+    return image.Id
+}
+
+func imageCursorValue(sort string, raw json.RawMessage) (interface{}, error) {
+    if sort == repository.ImageSortCreatedAt {
+        var t time.Time
+        err := json.Unmarshal(raw, &t)
+        return t, err
+    }
+    var n int
+    err := json.Unmarshal(raw, &n)
+    return n, err
+}
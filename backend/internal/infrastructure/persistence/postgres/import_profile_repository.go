@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewImportProfileRepository(db *gorm.DB) *ImportProfileRepository {
+	return &ImportProfileRepository{db: db}
+}
+
+func (r *ImportProfileRepository) Save(profile *entity.ImportProfile) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"range", "mapping"}),
+	}).Create(profile).Error
+}
+
+func (r *ImportProfileRepository) FindByName(name string) (*entity.ImportProfile, error) {
+	var profile entity.ImportProfile
+	if err := r.db.Where("name = ?", name).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *ImportProfileRepository) FindAll() ([]entity.ImportProfile, error) {
+	var profiles []entity.ImportProfile
+	if err := r.db.Order("name").Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -2,6 +2,11 @@ package postgres
 
 import (
 	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"encoding/json"
+	"errors"
+
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -21,22 +26,228 @@ func (r *QuoteRepository) Store(quote *entity.Quote) (int, error) {
 	return quote.Id, nil
 }
 
+func (r *QuoteRepository) StoreBatch(quotes []entity.Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(quotes, len(quotes)).Error
+}
+
 func (r *QuoteRepository) Update(quote *entity.Quote) error {
 	return r.db.Save(quote).Error
 }
 
+func (r *QuoteRepository) UpdateVersion(quote *entity.Quote, version int) error {
+	quote.Version = version + 1
+	result := r.db.Model(&entity.Quote{}).
+		Where("id = ? AND version = ?", quote.Id, version).
+		Select("*").Omit("id", "deleted_at").
+		Updates(quote)
+	if result.Error != nil {
+		quote.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		quote.Version = version
+		return r.missingOrConflict(quote.Id)
+	}
+	return nil
+}
+
+func (r *QuoteRepository) Delete(id int, version int) error {
+	query := r.db.Where("id = ?", id)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Delete(&entity.Quote{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrConflict(id)
+	}
+	return nil
+}
+
+// missingOrConflict explains why a versioned write matched no row.
+func (r *QuoteRepository) missingOrConflict(id int) error {
+	var count int64
+	if err := r.db.Model(&entity.Quote{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return repository.ErrNotFound
+	}
+	return repository.ErrVersionConflict
+}
+
 func (r *QuoteRepository) FindByID(id int) (*entity.Quote, error) {
 	var quote entity.Quote
 	if err := r.db.First(&quote, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
 		return nil, err
 	}
 	return &quote, nil
 }
 
+func (r *QuoteRepository) FindByIDs(ids []int) ([]entity.Quote, error) {
+	var quotes []entity.Quote
+	if len(ids) == 0 {
+		return quotes, nil
+	}
+	if err := r.db.Unscoped().Where("id IN ?", ids).Find(&quotes).Error; err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) Restore(id int) error {
+	return r.db.Unscoped().Model(&entity.Quote{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *QuoteRepository) SyncIDSequence() error {
+	return r.db.Exec(`SELECT setval(pg_get_serial_sequence('quotes', 'id'), (SELECT COALESCE(MAX(id), 0) + 1 FROM quotes), false)`).Error
+}
+
 func (r *QuoteRepository) FindAll() ([]entity.Quote, error) {
 	var quotes []entity.Quote
 	if err := r.db.Find(&quotes).Error; err != nil {
 		return nil, err
 	}
 	return quotes, nil
-} 
\ No newline at end of file
+}
+
+func (r *QuoteRepository) FindByBands(bands []int64, langs []string) ([]entity.Quote, error) {
+	var quotes []entity.Quote
+	if len(bands) == 0 || len(langs) == 0 {
+		return quotes, nil
+	}
+	err := r.db.Select("id", "natural_key", "lang", "fingerprint").
+		Where("bands && ?::bigint[] AND lang IN ?", pq.Int64Array(bands), langs).
+		Find(&quotes).Error
+	if err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) FindUnbanded(limit int) ([]entity.Quote, error) {
+	var quotes []entity.Quote
+	if err := r.db.Unscoped().Where("bands IS NULL").Order("id ASC").Limit(limit).Find(&quotes).Error; err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) SetFingerprints(quotes []entity.Quote) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range quotes {
+			err := tx.Unscoped().Model(&quotes[i]).Select("fingerprint", "bands").Updates(&quotes[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *QuoteRepository) List(filter repository.QuoteFilter) ([]entity.Quote, string, error) {
+	query := applyQuoteFilter(r.db.Model(&entity.Quote{}), filter)
+	if filter.Cursor != "" {
+		c, err := decodeCursor(filter.Cursor, "id", false)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("id > ?", c.ID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var quotes []entity.Quote
+	if err := query.Order("id ASC").Limit(limit + 1).Find(&quotes).Error; err != nil {
+		return nil, "", err
+	}
+	if len(quotes) <= limit {
+		return quotes, "", nil
+	}
+
+	quotes = quotes[:limit]
+	next, err := encodeCursor(cursor{Sort: "id", ID: int64(quotes[len(quotes)-1].Id)})
+	if err != nil {
+		return nil, "", err
+	}
+	return quotes, next, nil
+}
+
+func applyQuoteFilter(query *gorm.DB, filter repository.QuoteFilter) *gorm.DB {
+	if filter.Author != "" {
+		query = query.Where("LOWER(author) = LOWER(?)", filter.Author)
+	}
+	if filter.Work != "" {
+		query = query.Where("LOWER(work) = LOWER(?)", filter.Work)
+	}
+	if filter.License != "" {
+		query = query.Where("LOWER(license) = LOWER(?)", filter.License)
+	}
+	if filter.Tag != "" {
+		tag, _ := json.Marshal([]string{filter.Tag})
+		query = query.Where("tags::jsonb @> ?::jsonb", string(tag))
+	}
+	if filter.Lang != "" {
+		query = query.Where("lang = ?", filter.Lang)
+	}
+	if filter.AttributionRequired != nil {
+		query = query.Where("attribution_required = ?", *filter.AttributionRequired)
+	}
+	return query
+}
+
+func (r *QuoteRepository) FindByTranslationGroup(group string) ([]entity.Quote, error) {
+	var quotes []entity.Quote
+	if err := r.db.Where("translation_group = ?", group).Order("id").Find(&quotes).Error; err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) FindByNaturalKeys(keys []string) ([]entity.Quote, error) {
+	var quotes []entity.Quote
+	if len(keys) == 0 {
+		return quotes, nil
+	}
+	if err := r.db.Where("natural_key IN ?", keys).Find(&quotes).Error; err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) FindBySource(source string) ([]entity.Quote, error) {
+	var quotes []entity.Quote
+	if err := r.db.Where("import_source = ?", source).Find(&quotes).Error; err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) TouchImportRun(keys []string, run string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.db.Model(&entity.Quote{}).Where("natural_key IN ?", keys).Update("import_run", run).Error
+}
+
+func (r *QuoteRepository) Transaction(fn func(repo repository.QuoteRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&QuoteRepository{db: tx})
+	})
+}
+
+func (r *QuoteRepository) DeleteStale(source, run string) (int64, error) {
+	result := r.db.Where("import_source = ? AND (import_run IS NULL OR import_run <> ?)", source, run).Delete(&entity.Quote{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchConfigs maps the primary subtag of a quote's Lang to the Postgres
+// text-search configuration used to stem it. Other languages use "simple".
+var searchConfigs = map[string]string{
+	"da": "danish",
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"it": "italian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20"
+
+// escapedText HTML-escapes the quote text before ts_headline sees it, so the
+// <mark> tags are the only markup a snippet can carry. The parser reads the
+// entities as single tokens and never splits them.
+const escapedText = `replace(replace(replace(replace(replace(hits.text, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+// searchSchema adds the generated search_vector column, which AutoMigrate
+// cannot express, and its GIN index. quote_search_config must be immutable
+// to be used in a generated column.
+func searchSchema() []string {
+	subtags := make([]string, 0, len(searchConfigs))
+	for subtag := range searchConfigs {
+		subtags = append(subtags, subtag)
+	}
+	sort.Strings(subtags)
+
+	var cases strings.Builder
+	for _, subtag := range subtags {
+		fmt.Fprintf(&cases, " WHEN '%s' THEN '%s'::regconfig", subtag, searchConfigs[subtag])
+	}
+
+	return []string{
+		`CREATE OR REPLACE FUNCTION quote_search_config(lang text) RETURNS regconfig
+			LANGUAGE sql IMMUTABLE PARALLEL SAFE
+			AS $$ SELECT CASE lower(split_part(lang, '-', 1))` + cases.String() + ` ELSE 'simple'::regconfig END $$`,
+		`ALTER TABLE quotes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector(quote_search_config(lang), coalesce(text, '')), 'A') ||
+			setweight(to_tsvector(quote_search_config(lang), coalesce(author, '') || ' ' || coalesce(work, '')), 'B')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_quotes_search_vector ON quotes USING GIN (search_vector)`,
+	}
+}
+
+func createSearchColumn(db *gorm.DB) error {
+	for _, stmt := range searchSchema() {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchTSQuery builds the tsquery for a search. Without a language the
+// query is parsed with every configuration and the results ORed, so stemmed
+// vectors match whatever their language; the query stays a constant, which
+// lets the GIN index serve it.
+func searchTSQuery(search repository.QuoteSearch) (string, []interface{}) {
+	if search.Lang != "" {
+		return "websearch_to_tsquery(quote_search_config(?), ?)", []interface{}{search.Lang, search.Query}
+	}
+
+	configs := make([]string, 0, len(searchConfigs)+1)
+	for _, config := range searchConfigs {
+		configs = append(configs, config)
+	}
+	sort.Strings(configs)
+	configs = append(configs, "simple")
+
+	parts := make([]string, len(configs))
+	args := make([]interface{}, len(configs))
+	for i, config := range configs {
+		parts[i] = fmt.Sprintf("websearch_to_tsquery('%s', ?)", config)
+		args[i] = search.Query
+	}
+	return strings.Join(parts, " || "), args
+}
+
+// searchMatches returns the FROM and WHERE clauses shared by Search and
+// CountSearch. The matched rows expose the tsquery as search.query.
+func searchMatches(search repository.QuoteSearch) (string, []interface{}) {
+	tsquery, args := searchTSQuery(search)
+	sql := "FROM quotes, (SELECT " + tsquery + " AS query) AS search" +
+		" WHERE quotes.deleted_at IS NULL AND quotes.search_vector @@ search.query"
+	if search.Lang != "" {
+		sql += " AND LOWER(quotes.lang) = LOWER(?)"
+		args = append(args, search.Lang)
+	}
+	if search.Tag != "" {
+		tag, _ := json.Marshal([]string{search.Tag})
+		sql += " AND quotes.tags::jsonb @> ?::jsonb"
+		args = append(args, string(tag))
+	}
+	return sql, args
+}
+
+// searchRow is a quote scanned together with its rank and snippet.
+type searchRow struct {
+	entity.Quote
+	Rank    float32
+	Snippet string
+}
+
+func (r *QuoteRepository) Search(search repository.QuoteSearch) ([]repository.QuoteSearchHit, string, error) {
+	matches, args := searchMatches(search)
+	sql := "SELECT hits.*, ts_headline(quote_search_config(hits.lang), " + escapedText + ", hits.query, '" + headlineOptions + "') AS snippet" +
+		" FROM (SELECT quotes.*, search.query, ts_rank_cd(quotes.search_vector, search.query) AS rank " + matches + ") AS hits"
+
+	if search.Cursor != "" {
+		c, err := decodeCursor(search.Cursor, "rank", true)
+		if err != nil {
+			return nil, "", err
+		}
+		var rank float32
+		if err := json.Unmarshal(c.Value, &rank); err != nil {
+			return nil, "", repository.ErrInvalidCursor
+		}
+		sql += " WHERE hits.rank < ? OR (hits.rank = ? AND hits.id > ?)"
+		args = append(args, rank, rank, c.ID)
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	sql += " ORDER BY hits.rank DESC, hits.id ASC LIMIT ?"
+	args = append(args, limit+1)
+
+	var rows []searchRow
+	if err := r.db.Raw(sql, args...).Scan(&rows).Error; err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		value, err := json.Marshal(last.Rank)
+		if err != nil {
+			return nil, "", err
+		}
+		next, err = encodeCursor(cursor{Sort: "rank", Desc: true, Value: value, ID: int64(last.Id)})
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	hits := make([]repository.QuoteSearchHit, len(rows))
+	for i, row := range rows {
+		hits[i] = repository.QuoteSearchHit{Quote: row.Quote, Rank: float64(row.Rank), Snippet: row.Snippet}
+	}
+	return hits, next, nil
+}
+
+func (r *QuoteRepository) CountSearch(search repository.QuoteSearch) (int64, error) {
+	matches, args := searchMatches(search)
+	var total int64
+	if err := r.db.Raw("SELECT count(*) "+matches, args...).Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
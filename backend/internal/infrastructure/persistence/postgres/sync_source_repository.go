@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SyncSourceRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncSourceRepository(db *gorm.DB) *SyncSourceRepository {
+	return &SyncSourceRepository{db: db}
+}
+
+func (r *SyncSourceRepository) Store(source *entity.SyncSource) (int, error) {
+	if err := r.db.Create(source).Error; err != nil {
+		return 0, err
+	}
+	return source.Id, nil
+}
+
+// Update writes every field of an existing source. Unlike Save it never
+// inserts, so a run that finishes after its source was deleted does not bring
+// it back.
+func (r *SyncSourceRepository) Update(source *entity.SyncSource) error {
+	result := r.db.Model(&entity.SyncSource{}).Where("id = ?", source.Id).
+		Select("*").Omit("id", "created_at").Updates(source)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SyncSourceRepository) Delete(id int) error {
+	result := r.db.Delete(&entity.SyncSource{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SyncSourceRepository) FindByID(id int) (*entity.SyncSource, error) {
+	var source entity.SyncSource
+	if err := r.db.First(&source, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &source, nil
+}
+
+func (r *SyncSourceRepository) FindAll() ([]entity.SyncSource, error) {
+	var sources []entity.SyncSource
+	if err := r.db.Order("id").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+func (r *SyncSourceRepository) FindDue(now time.Time) ([]entity.SyncSource, error) {
+	var sources []entity.SyncSource
+	if err := r.db.Where("NOT paused AND next_run_at <= ?", now).Order("next_run_at").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// TagRepository edits tags across the quotes and flyers tables.
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) FindQuotes(selector repository.TagSelector) ([]entity.Quote, error) {
+	query := applyTagSelector(r.db.Model(&entity.Quote{}), selector)
+	if len(selector.QuoteIDs) > 0 {
+		query = query.Where("id IN ?", selector.QuoteIDs)
+	}
+	var quotes []entity.Quote
+	if err := query.Order("id").Find(&quotes).Error; err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+func (r *TagRepository) FindFlyers(selector repository.TagSelector) ([]entity.Flyer, error) {
+	query := applyTagSelector(r.db.Model(&entity.Flyer{}), selector)
+	if len(selector.FlyerIDs) > 0 {
+		query = query.Where("id IN ?", selector.FlyerIDs)
+	}
+	var flyers []entity.Flyer
+	if err := query.Order("id").Find(&flyers).Error; err != nil {
+		return nil, err
+	}
+	return flyers, nil
+}
+
+func (r *TagRepository) SetQuoteTags(id int, tags []string) error {
+	return r.db.Model(&entity.Quote{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"tags":    tagsJSON(tags),
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *TagRepository) SetFlyerTags(id uint, tags []string) error {
+	return r.db.Model(&entity.Flyer{}).Where("id = ?", id).Update("tags", tagsJSON(tags)).Error
+}
+
+func (r *TagRepository) Transaction(fn func(repo repository.TagRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&TagRepository{db: tx})
+	})
+}
+
+// applyTagSelector narrows a quotes or flyers query, both of which keep their
+// tags as a JSON array in the tags column.
+func applyTagSelector(query *gorm.DB, selector repository.TagSelector) *gorm.DB {
+	if len(selector.Tags) > 0 {
+		lowered := make([]string, len(selector.Tags))
+		for i, tag := range selector.Tags {
+			lowered[i] = strings.ToLower(tag)
+		}
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(tags, '[]')::jsonb) AS t(tag) WHERE LOWER(t.tag) IN ?)", lowered)
+	}
+	if selector.Lang != "" {
+		query = query.Where("LOWER(lang) = LOWER(?)", selector.Lang)
+	}
+	return query
+}
+
+// tagsJSON encodes tags the way the json serializer stores them, so a nil
+// list is written as [] rather than null.
+func tagsJSON(tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
@@ -1,12 +1,18 @@
 package s3
 
 import (
+	"backend/internal/domain/service"
 	"bytes"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -29,6 +35,10 @@ func NewS3Service() (*S3Service, error) {
 	return &S3Service{session: sess}, nil
 }
 
+func (s *S3Service) ImageKey(fileName string) string {
+	return os.Getenv("S3_IMAGES_DIR_PATH") + fileName
+}
+
 func (s *S3Service) UploadImage(filePath, fileName string) error {
 	return s.uploadFile(filePath, fileName, os.Getenv("S3_IMAGES_DIR_PATH"))
 }
@@ -37,6 +47,98 @@ func (s *S3Service) UploadMetadata(filePath, fileName string) error {
 	return s.uploadFile(filePath, fileName, "")
 }
 
+func (s *S3Service) HeadObject(key string) (*service.ObjectInfo, error) {
+	svc := s3.New(s.session)
+
+	out, err := svc.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &service.ObjectInfo{
+		ContentType:   aws.StringValue(out.ContentType),
+		ContentLength: aws.Int64Value(out.ContentLength),
+		ETag:          aws.StringValue(out.ETag),
+		LastModified:  aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+func (s *S3Service) GetObject(key, byteRange, ifMatch string) (*service.Object, error) {
+	svc := s3.New(s.session)
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
+		Key:    aws.String(key),
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+	if ifMatch != "" {
+		input.IfMatch = aws.String(ifMatch)
+	}
+
+	out, err := svc.GetObject(input)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &service.Object{
+		ObjectInfo: service.ObjectInfo{
+			ContentType:   aws.StringValue(out.ContentType),
+			ContentLength: aws.Int64Value(out.ContentLength),
+			ETag:          aws.StringValue(out.ETag),
+			LastModified:  aws.TimeValue(out.LastModified),
+		},
+		ContentRange: aws.StringValue(out.ContentRange),
+		Body:         out.Body,
+	}, nil
+}
+
+func (s *S3Service) PresignGetObject(key string, ttl time.Duration) (string, error) {
+	svc := s3.New(s.session)
+
+	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
+		Key:    aws.String(key),
+	})
+	url, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %v", key, err)
+	}
+	return url, nil
+}
+
+func (s *S3Service) DeleteObject(key string) error {
+	svc := s3.New(s.session)
+
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(os.Getenv("S3_BUCKET_NAME")),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	return nil
+}
+
+// translateError maps S3 error codes onto the domain errors.
+func translateError(err error) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return service.ErrObjectNotFound
+		case "InvalidRange":
+			return service.ErrInvalidRange
+		case "PreconditionFailed":
+			return service.ErrObjectChanged
+		}
+	}
+	return fmt.Errorf("S3 request failed: %v", err)
+}
+
 func (s *S3Service) uploadFile(filePath, fileName, dirPath string) error {
 	svc := s3.New(s.session)
 	bucketName := os.Getenv("S3_BUCKET_NAME")
@@ -58,13 +160,24 @@ func (s *S3Service) uploadFile(filePath, fileName, dirPath string) error {
 	}
 
 	_, err = svc.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
-		Body:   bytes.NewReader(fileData),
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(objectKey),
+		Body:        bytes.NewReader(fileData),
+		ContentType: aws.String(contentType(fileName, fileData)),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %v", err)
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// contentType picks the Content-Type stored with an object from its file
+// extension, sniffing the content when the extension is unknown. Without it
+// S3 serves everything as binary/octet-stream.
+func contentType(fileName string, data []byte) string {
+	if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+		return byExt
+	}
+	return http.DetectContentType(data)
+}
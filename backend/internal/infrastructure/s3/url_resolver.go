@@ -0,0 +1,65 @@
+package s3
+
+import (
+	"backend/internal/config"
+	"backend/internal/domain/service"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type urlResolver struct {
+	strategy   string
+	cdnBaseURL string
+	bucket     string
+	region     string
+	presignTTL time.Duration
+	s3Service  service.S3Service
+}
+
+// NewURLResolver returns the resolver for the configured IMAGE_URL_STRATEGY.
+// Only object keys are persisted, so switching strategy or CDN host needs no
+// data migration.
+func NewURLResolver(cfg *config.Config, s3Service service.S3Service) service.URLResolver {
+	return &urlResolver{
+		strategy:   cfg.URLStrategy,
+		cdnBaseURL: strings.TrimRight(cfg.CDNBaseURL, "/"),
+		bucket:     cfg.S3BucketName,
+		region:     cfg.S3Region,
+		presignTTL: cfg.PresignTTL,
+		s3Service:  s3Service,
+	}
+}
+
+func (r *urlResolver) ObjectURL(key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+
+	if r.strategy == config.URLStrategyPresigned {
+		return r.s3Service.PresignGetObject(key, r.presignTTL)
+	}
+	return r.PublicURL(key), nil
+}
+
+// PublicURL serves from CDN_BASE_URL when one is configured, even under the
+// presigned strategy, and from the S3 bucket otherwise.
+func (r *urlResolver) PublicURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	if r.strategy == config.URLStrategyCDN || r.cdnBaseURL != "" {
+		return r.cdnBaseURL + "/" + escapeKey(key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", r.bucket, r.region, escapeKey(key))
+}
+
+// escapeKey percent-encodes each path segment of an object key.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
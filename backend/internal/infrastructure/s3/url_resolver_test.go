@@ -0,0 +1,75 @@
+package s3
+
+import (
+	"backend/internal/config"
+	"testing"
+)
+
+func TestURLResolverObjectURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		key  string
+		want string
+	}{
+		{
+			name: "cdn base url",
+			cfg:  config.Config{URLStrategy: config.URLStrategyCDN, CDNBaseURL: "https://cdn.example.com/"},
+			key:  "media/images/12_diwali offer.jpg",
+			want: "https://cdn.example.com/media/images/12_diwali%20offer.jpg",
+		},
+		{
+			name: "virtual-hosted s3",
+			cfg:  config.Config{URLStrategy: config.URLStrategyS3, S3BucketName: "content", S3Region: "us-east-1"},
+			key:  "media/images/12_offer.jpg",
+			want: "https://content.s3.us-east-1.amazonaws.com/media/images/12_offer.jpg",
+		},
+		{
+			name: "empty key",
+			cfg:  config.Config{URLStrategy: config.URLStrategyS3},
+			key:  "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewURLResolver(&tt.cfg, nil).ObjectURL(tt.key)
+			if err != nil {
+				t.Fatalf("ObjectURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ObjectURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLResolverPublicURLNeverPresigns(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{
+			name: "presigned without cdn",
+			cfg:  config.Config{URLStrategy: config.URLStrategyPresigned, S3BucketName: "content", S3Region: "us-east-1"},
+			want: "https://content.s3.us-east-1.amazonaws.com/media/images/12_offer.jpg",
+		},
+		{
+			name: "presigned with cdn",
+			cfg:  config.Config{URLStrategy: config.URLStrategyPresigned, CDNBaseURL: "https://cdn.example.com"},
+			want: "https://cdn.example.com/media/images/12_offer.jpg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a nil S3 service panics if the resolver tries to presign
+			got := NewURLResolver(&tt.cfg, nil).PublicURL("media/images/12_offer.jpg")
+			if got != tt.want {
+				t.Errorf("PublicURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
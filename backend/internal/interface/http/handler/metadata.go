@@ -40,6 +40,20 @@ type FlyersMetadata struct {
 	Schema      Schema `json:"schema,omitempty"`
 }
 
+type Schema struct {
+	Format   string `json:"format"`
+	Encoding string `json:"encoding"`
+	FileType string `json:"fileType"`
+}
+
+type QuotesMetadata struct {
+	Version     string `json:"version"`
+	LastUpdated string `json:"lastUpdated"`
+	TotalQuotes int    `json:"totalQuotes"`
+	URL         string `json:"url"`
+	Schema      Schema `json:"schema"`
+}
+
 type MetaDataImages struct {
 	Images         []images.Flyer `json:"images"`
 	ImagesMetadata FlyersMetadata `json:"metadata"`
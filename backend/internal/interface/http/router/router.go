@@ -17,16 +17,8 @@ func RegisterHandlers(mux *http.ServeMux) {
 	}
 	imageHandler := handler.NewImageHandler(database)
 
-	// handlers regarding quotes
-	// http.HandleFunc("/quotes/import", handler.HandleQuotesImport)
-	mux.Handle("/quotes/import", middleware.CheckQuotesLink(
-		http.HandlerFunc(handler.HandleQuotesImport)),
-	)
-	// checks if all the fields are present and if the JSON is valid
-	// also http method is checked
-	mux.Handle("/quotes", middleware.QuotesJsonAndMethodValidator(
-		http.HandlerFunc(handler.HandleQuotesUpload),
-	))
+	// quotes are imported and created through the delivery/http quote
+	// handler, which reads Google Sheets with the configured credentials
 
 	// handlers regarding images
 
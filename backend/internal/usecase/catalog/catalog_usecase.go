@@ -0,0 +1,176 @@
+package catalog
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownCatalog is returned for a media type that has no catalog.
+var ErrUnknownCatalog = errors.New("unknown catalog")
+
+// ErrInvalidSince is returned when since is negative or ahead of the latest
+// published version.
+var ErrInvalidSince = errors.New("invalid since version")
+
+// mediaTypes maps the media types of the metadata API to their catalogs.
+var mediaTypes = map[string]string{
+	"image":                   entity.CatalogImages,
+	"quote":                   entity.CatalogQuotes,
+	"daily":                   entity.CatalogDailyQuotes,
+	entity.CatalogImages:      entity.CatalogImages,
+	entity.CatalogQuotes:      entity.CatalogQuotes,
+	entity.CatalogDailyQuotes: entity.CatalogDailyQuotes,
+}
+
+// ChangeSet brings a client from version Since to Version. Each changed
+// item appears once, with the last version that touched it. When the
+// change log no longer reaches back to Since, ResyncRequired is set and the
+// client downloads Snapshot instead.
+type ChangeSet struct {
+	Catalog        string                 `json:"catalog"`
+	Since          int                    `json:"since"`
+	Version        int                    `json:"version"`
+	ResyncRequired bool                   `json:"resyncRequired"`
+	Snapshot       string                 `json:"snapshot,omitempty"`
+	Changes        []entity.CatalogChange `json:"changes"`
+}
+
+// CatalogUseCase serves the change log of the published metadata catalogs
+// to clients syncing incrementally.
+type CatalogUseCase struct {
+	catalogs repository.CatalogRepository
+}
+
+func NewCatalogUseCase(catalogs repository.CatalogRepository) *CatalogUseCase {
+	return &CatalogUseCase{
+		catalogs: catalogs,
+	}
+}
+
+// Changes returns what changed in the catalog of mediaType after version
+// since. Deltas are served from the oldest kept snapshot on; older versions,
+// and versions before a snapshot published without a delta, need a resync.
+// It returns repository.ErrNotFound when the catalog was never published.
+func (uc *CatalogUseCase) Changes(mediaType string, since int) (*ChangeSet, error) {
+	catalog, ok := mediaTypes[strings.TrimSpace(mediaType)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCatalog, mediaType)
+	}
+	if since < 0 {
+		return nil, fmt.Errorf("%w: %d is negative", ErrInvalidSince, since)
+	}
+
+	var set *ChangeSet
+	err := uc.catalogs.ReadOnly(func(repo repository.CatalogRepository) error {
+		var err error
+		set, err = changes(repo, catalog, since)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// changes reads the change set through repo. The snapshots and the change
+// log are read in one transaction so a prune cannot remove changes between
+// the two reads; the delta floor is checked again after the changes are
+// read all the same, and a client that fell behind it resyncs.
+func changes(repo repository.CatalogRepository, catalog string, since int) (*ChangeSet, error) {
+	snapshots, err := repo.ListSnapshots(catalog)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	latest := snapshots[len(snapshots)-1]
+	if since > latest.Version {
+		return nil, fmt.Errorf("%w: %d is ahead of version %d", ErrInvalidSince, since, latest.Version)
+	}
+
+	set := &ChangeSet{
+		Catalog: catalog,
+		Since:   since,
+		Version: latest.Version,
+		Changes: []entity.CatalogChange{},
+	}
+	if since == latest.Version {
+		return set, nil
+	}
+	if since < deltaFloor(snapshots) {
+		set.ResyncRequired = true
+		set.Snapshot = latest.File
+		return set, nil
+	}
+
+	found, err := repo.FindChanges(catalog, since)
+	if err != nil {
+		return nil, err
+	}
+	kept, err := repo.ListSnapshots(catalog)
+	if err != nil {
+		return nil, err
+	}
+	if len(kept) == 0 || since < deltaFloor(kept) {
+		set.ResyncRequired = true
+		set.Snapshot = latest.File
+		return set, nil
+	}
+	set.Changes = collapse(found)
+	return set, nil
+}
+
+// deltaFloor is the oldest version a client can sync from: the oldest kept
+// snapshot, or the newest one published without a delta, whichever is
+// newer.
+func deltaFloor(snapshots []entity.CatalogSnapshot) int {
+	floor := snapshots[0].Version
+	for _, s := range snapshots {
+		if s.Delta == "" && s.Version > floor {
+			floor = s.Version
+		}
+	}
+	return floor
+}
+
+// collapse folds the changes of several versions into one per item, in the
+// order items first changed. An item added and then deleted drops out; an
+// item deleted and then added again, or updated, becomes an update.
+func collapse(changes []entity.CatalogChange) []entity.CatalogChange {
+	type state struct {
+		existed bool
+		last    entity.CatalogChange
+	}
+	var order []string
+	items := make(map[string]*state)
+	for _, c := range changes {
+		st, ok := items[c.ItemId]
+		if !ok {
+			st = &state{existed: c.Op != entity.ChangeAdd}
+			items[c.ItemId] = st
+			order = append(order, c.ItemId)
+		}
+		st.last = c
+	}
+
+	out := make([]entity.CatalogChange, 0, len(order))
+	for _, id := range order {
+		st := items[id]
+		c := st.last
+		switch {
+		case c.Op == entity.ChangeDelete && !st.existed:
+			continue
+		case c.Op == entity.ChangeDelete:
+		case st.existed:
+			c.Op = entity.ChangeUpdate
+		default:
+			c.Op = entity.ChangeAdd
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"testing"
+)
+
+type fakeCatalogs struct {
+	repository.CatalogRepository
+	snapshots []entity.CatalogSnapshot
+	changes   []entity.CatalogChange
+}
+
+func (c *fakeCatalogs) ReadOnly(fn func(repository.CatalogRepository) error) error {
+	return fn(c)
+}
+
+func (c *fakeCatalogs) ListSnapshots(catalog string) ([]entity.CatalogSnapshot, error) {
+	var found []entity.CatalogSnapshot
+	for _, s := range c.snapshots {
+		if s.Catalog == catalog {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func (c *fakeCatalogs) FindChanges(catalog string, since int) ([]entity.CatalogChange, error) {
+	var found []entity.CatalogChange
+	for _, ch := range c.changes {
+		if ch.Catalog == catalog && ch.Version > since {
+			found = append(found, ch)
+		}
+	}
+	return found, nil
+}
+
+func change(version int, op, id string) entity.CatalogChange {
+	return entity.CatalogChange{Catalog: entity.CatalogQuotes, Version: version, ItemId: id, Op: op}
+}
+
+func newFixture() *CatalogUseCase {
+	// versions 3 to 6 are kept; 3 was the first published with a delta
+	return NewCatalogUseCase(&fakeCatalogs{
+		snapshots: []entity.CatalogSnapshot{
+			{Catalog: entity.CatalogQuotes, Version: 3, File: "quotesMetadata.v3.json", Delta: "quotesMetadata.v3.delta.json"},
+			{Catalog: entity.CatalogQuotes, Version: 4, File: "quotesMetadata.v4.json", Delta: "quotesMetadata.v4.delta.json"},
+			{Catalog: entity.CatalogQuotes, Version: 5, File: "quotesMetadata.v5.json", Delta: "quotesMetadata.v5.delta.json"},
+			{Catalog: entity.CatalogQuotes, Version: 6, File: "quotesMetadata.v6.json", Delta: "quotesMetadata.v6.delta.json"},
+		},
+		changes: []entity.CatalogChange{
+			change(4, entity.ChangeAdd, "7"),
+			change(4, entity.ChangeUpdate, "1"),
+			change(4, entity.ChangeDelete, "2"),
+			change(5, entity.ChangeAdd, "8"),
+			change(5, entity.ChangeUpdate, "7"),
+			change(5, entity.ChangeAdd, "2"),
+			change(6, entity.ChangeDelete, "8"),
+			change(6, entity.ChangeDelete, "1"),
+		},
+	})
+}
+
+func TestChangesCollapsesVersions(t *testing.T) {
+	set, err := newFixture().Changes("quote", 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if set.ResyncRequired || set.Catalog != entity.CatalogQuotes || set.Version != 6 {
+		t.Fatalf("unexpected change set: %+v", set)
+	}
+
+	var got []string
+	for _, c := range set.Changes {
+		got = append(got, c.Op+":"+c.ItemId)
+	}
+	// 7 is added and updated, 2 deleted and added back, 8 added and deleted
+	want := []string{"add:7", "delete:1", "update:2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+	if set.Changes[0].Version != 5 {
+		t.Errorf("expected item 7 at version 5, got %d", set.Changes[0].Version)
+	}
+}
+
+func TestChangesRequiresResync(t *testing.T) {
+	uc := newFixture()
+
+	set, err := uc.Changes("quotes", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set.ResyncRequired || set.Snapshot != "quotesMetadata.v6.json" || len(set.Changes) != 0 {
+		t.Errorf("expected a compacted version to need a resync, got %+v", set)
+	}
+
+	set, err = uc.Changes("quote", 6)
+	if err != nil || set.ResyncRequired || len(set.Changes) != 0 {
+		t.Errorf("expected an up to date client to get no changes, got %+v, %v", set, err)
+	}
+
+	if _, err := uc.Changes("quote", 7); !errors.Is(err, ErrInvalidSince) {
+		t.Errorf("expected %v, got %v", ErrInvalidSince, err)
+	}
+	if _, err := uc.Changes("video", 3); !errors.Is(err, ErrUnknownCatalog) {
+		t.Errorf("expected %v, got %v", ErrUnknownCatalog, err)
+	}
+	if _, err := uc.Changes("image", 0); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected %v, got %v", repository.ErrNotFound, err)
+	}
+}
+
+// pruningCatalogs prunes the oldest snapshot, and the changes of its
+// version, while the change log is being read.
+type pruningCatalogs struct {
+	*fakeCatalogs
+}
+
+func (c pruningCatalogs) FindChanges(catalog string, since int) ([]entity.CatalogChange, error) {
+	found, err := c.fakeCatalogs.FindChanges(catalog, since)
+	oldest := c.snapshots[0].Version
+	c.snapshots = c.snapshots[1:]
+	var kept []entity.CatalogChange
+	for _, ch := range c.changes {
+		if ch.Version > oldest+1 {
+			kept = append(kept, ch)
+		}
+	}
+	c.changes = kept
+	return found, err
+}
+
+func (c pruningCatalogs) ReadOnly(fn func(repository.CatalogRepository) error) error {
+	return fn(c)
+}
+
+func TestChangesRechecksFloorAfterPrune(t *testing.T) {
+	uc := newFixture()
+	uc.catalogs = pruningCatalogs{uc.catalogs.(*fakeCatalogs)}
+
+	set, err := uc.Changes("quote", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set.ResyncRequired || len(set.Changes) != 0 {
+		t.Errorf("expected a resync once version 3 was pruned, got %+v", set)
+	}
+}
@@ -16,17 +16,21 @@ import (
 	"strings"
 )
 
+const maxPageSize = 100
+
 type ImageUseCase struct {
 	imageRepo       repository.ImageRepository
 	s3Service      service.S3Service
 	metadataService service.MetadataService
+	urlResolver     service.URLResolver
 }
 
-func NewImageUseCase(repo repository.ImageRepository, s3 service.S3Service, meta service.MetadataService) *ImageUseCase {
+func NewImageUseCase(repo repository.ImageRepository, s3 service.S3Service, meta service.MetadataService, urls service.URLResolver) *ImageUseCase {
 	return &ImageUseCase{
 		imageRepo:       repo,
 		s3Service:      s3,
 		metadataService: meta,
+		urlResolver:     urls,
 	}
 }
 
@@ -55,8 +59,8 @@ func (uc *ImageUseCase) UploadImage(file multipart.File, header *multipart.FileH
 	}
 	flyer.Id = id
 
-	// Update the URL with the ID
-	flyer.Url = fmt.Sprintf("%s/%d_%s", os.Getenv("S3_BUCKET_NAME"), id, header.Filename)
+	// Now that the ID is known, record the object key
+	flyer.Key = uc.s3Service.ImageKey(fmt.Sprintf("%d_%s", id, header.Filename))
 	if err := uc.imageRepo.Update(flyer); err != nil {
 		return nil, err
 	}
@@ -69,6 +73,9 @@ func (uc *ImageUseCase) UploadImage(file multipart.File, header *multipart.FileH
 		return nil, err
 	}
 
+	if err := uc.resolveURLs(flyer); err != nil {
+		return nil, err
+	}
 	return flyer, nil
 }
 
@@ -111,7 +118,6 @@ func (uc *ImageUseCase) createFlyer(filePath, filename string) (*entity.Flyer, e
 			FileName:    filename,
 		},
 		Lang: "en-US",
-		Url:  fmt.Sprintf("%s/%s", os.Getenv("S3_BUCKET_NAME"), filename),
 	}, nil
 }
 
@@ -120,32 +126,30 @@ func (uc *ImageUseCase) extractImageTags(filename string) []string {
 	return strings.Split(name, "_")
 }
 
+// PublishMetadata republishes imagesMetadata.json after flyers were changed
+// outside this use case.
+func (uc *ImageUseCase) PublishMetadata() error {
+	return uc.updateMetadata()
+}
+
 func (uc *ImageUseCase) updateMetadata() error {
 	images, err := uc.imageRepo.FindAll()
 	if err != nil {
 		return err
 	}
+	for i := range images {
+		uc.publicURLs(&images[i])
+	}
 	return uc.metadataService.UpdateImageMetadata(images)
 }
 
 func (uc *ImageUseCase) ImportImages(importDir string) (successCount, failureCount int, err error) {
-	// Validate import directory exists
-	if _, err := os.Stat(importDir); os.IsNotExist(err) {
-		return 0, 0, fmt.Errorf("import directory %s does not exist", importDir)
-	}
-
-	// Process each file in the directory
-	entries, err := os.ReadDir(importDir)
+	filenames, err := readImportDir(importDir)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to read directory: %v", err)
+		return 0, 0, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		filename := entry.Name()
+	for _, filename := range filenames {
 		if !isValidImageFile(filename) {
 			failureCount++
 			continue
@@ -167,6 +171,84 @@ func (uc *ImageUseCase) ImportImages(importDir string) (successCount, failureCou
 	return successCount, failureCount, nil
 }
 
+// ImportPreview lists what ImportImages would do with a directory. Files in
+// Existing were imported before and would be imported again as new flyers.
+type ImportPreview struct {
+	Create   []entity.Flyer `json:"create"`
+	Existing []ExistingFile `json:"existing"`
+	Skip     []SkippedFile  `json:"skip"`
+}
+
+// ExistingFile is a file whose name matches already imported flyers.
+type ExistingFile struct {
+	FileName string `json:"fileName"`
+	Ids      []uint `json:"ids"`
+}
+
+// SkippedFile is a file an import would leave out, and why.
+type SkippedFile struct {
+	FileName string `json:"fileName"`
+	Reason   string `json:"reason"`
+}
+
+// PreviewImport decodes and validates every file ImportImages would import
+// and returns the flyers it would create, without writing to the database or
+// S3. Files imported before are listed apart from the new ones.
+func (uc *ImageUseCase) PreviewImport(importDir string) (*ImportPreview, error) {
+	filenames, err := readImportDir(importDir)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := uc.imageRepo.FindByFileNames(filenames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up imported files: %w", err)
+	}
+	existing := make(map[string][]uint, len(imported))
+	for _, flyer := range imported {
+		existing[flyer.Design.FileName] = append(existing[flyer.Design.FileName], flyer.Id)
+	}
+
+	preview := &ImportPreview{Create: []entity.Flyer{}, Existing: []ExistingFile{}, Skip: []SkippedFile{}}
+	for _, filename := range filenames {
+		if !isValidImageFile(filename) {
+			preview.Skip = append(preview.Skip, SkippedFile{FileName: filename, Reason: "unsupported file type"})
+			continue
+		}
+		if ids, ok := existing[filename]; ok {
+			preview.Existing = append(preview.Existing, ExistingFile{FileName: filename, Ids: ids})
+			continue
+		}
+		flyer, err := uc.createFlyer(filepath.Join(importDir, filename), filename)
+		if err != nil {
+			preview.Skip = append(preview.Skip, SkippedFile{FileName: filename, Reason: err.Error()})
+			continue
+		}
+		preview.Create = append(preview.Create, *flyer)
+	}
+	return preview, nil
+}
+
+// readImportDir lists the regular files in an import directory.
+func readImportDir(importDir string) ([]string, error) {
+	if _, err := os.Stat(importDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("import directory %s does not exist", importDir)
+	}
+
+	entries, err := os.ReadDir(importDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %v", err)
+	}
+
+	var filenames []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			filenames = append(filenames, entry.Name())
+		}
+	}
+	return filenames, nil
+}
+
 func (uc *ImageUseCase) processImageFile(importDir, filename string) error {
 	sourcePath := filepath.Join(importDir, filename)
 	flyer, err := uc.createFlyer(sourcePath, filename)
@@ -180,10 +262,10 @@ func (uc *ImageUseCase) processImageFile(importDir, filename string) error {
 	}
 
 	flyer.Id = id
-	flyer.Url = fmt.Sprintf("%s/%d_%s", os.Getenv("S3_BUCKET_NAME"), id, filename)
-	
+	flyer.Key = uc.s3Service.ImageKey(fmt.Sprintf("%d_%s", id, filename))
+
 	if err := uc.imageRepo.Update(flyer); err != nil {
-		return fmt.Errorf("failed to update flyer URL: %w", err)
+		return fmt.Errorf("failed to update flyer key: %w", err)
 	}
 
 	if err := uc.s3Service.UploadImage(sourcePath, fmt.Sprintf("%d_%s", id, filename)); err != nil {
@@ -193,6 +275,100 @@ func (uc *ImageUseCase) processImageFile(importDir, filename string) error {
 	return nil
 }
 
+// GetImage returns a single flyer, or repository.ErrNotFound.
+func (uc *ImageUseCase) GetImage(id uint) (*entity.Flyer, error) {
+	flyer, err := uc.imageRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.resolveURLs(flyer); err != nil {
+		return nil, err
+	}
+	return flyer, nil
+}
+
+// ImageFileInfo resolves which stored object serves a flyer at the requested
+// width (0 for the original) and returns its key and object metadata.
+func (uc *ImageUseCase) ImageFileInfo(id uint, width int) (string, *service.ObjectInfo, error) {
+	flyer, err := uc.imageRepo.FindByID(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := uc.nearestObjectKey(flyer, width)
+	info, err := uc.s3Service.HeadObject(key)
+	if err != nil {
+		return "", nil, err
+	}
+	return key, info, nil
+}
+
+// OpenImageFile opens a stored image object, optionally limited to a byte
+// range. It fails with service.ErrObjectChanged unless the object still has
+// etag, so the content always matches the metadata ImageFileInfo returned.
+func (uc *ImageUseCase) OpenImageFile(key, byteRange, etag string) (*service.Object, error) {
+	return uc.s3Service.GetObject(key, byteRange, etag)
+}
+
+// ListImages returns one page of flyers matching the filter and the cursor
+// for the next page.
+func (uc *ImageUseCase) ListImages(filter repository.ImageFilter) ([]entity.Flyer, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = repository.DefaultImagePageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	flyers, next, err := uc.imageRepo.List(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range flyers {
+		if err := uc.resolveURLs(&flyers[i]); err != nil {
+			return nil, "", err
+		}
+	}
+	return flyers, next, nil
+}
+
+// objectKey returns the flyer's S3 key. Rows written before keys were stored
+// fall back to the "<id>_<fileName>" naming used at upload.
+func (uc *ImageUseCase) objectKey(flyer *entity.Flyer) string {
+	if flyer.Key != "" {
+		return flyer.Key
+	}
+	return uc.s3Service.ImageKey(fmt.Sprintf("%d_%s", flyer.Id, flyer.Design.FileName))
+}
+
+// resolveURLs derives the client-facing URLs of a flyer and its renditions
+// from their stored keys.
+func (uc *ImageUseCase) resolveURLs(flyer *entity.Flyer) error {
+	url, err := uc.urlResolver.ObjectURL(uc.objectKey(flyer))
+	if err != nil {
+		return fmt.Errorf("failed to resolve URL for image %d: %w", flyer.Id, err)
+	}
+	flyer.Url = url
+
+	for i := range flyer.Renditions {
+		url, err := uc.urlResolver.ObjectURL(flyer.Renditions[i].Key)
+		if err != nil {
+			return fmt.Errorf("failed to resolve URL for image %d: %w", flyer.Id, err)
+		}
+		flyer.Renditions[i].Url = url
+	}
+	return nil
+}
+
+// publicURLs sets the stable URLs published in imagesMetadata.json. Presigned
+// URLs expire and differ on every publish, so they are only handed out by
+// the API.
+func (uc *ImageUseCase) publicURLs(flyer *entity.Flyer) {
+	flyer.Url = uc.urlResolver.PublicURL(uc.objectKey(flyer))
+	for i := range flyer.Renditions {
+		flyer.Renditions[i].Url = uc.urlResolver.PublicURL(flyer.Renditions[i].Key)
+	}
+}
+
 func isValidImageFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
 	return ext == ".jpg" || ext == ".jpeg" || ext == ".png"
@@ -0,0 +1,16 @@
+package image
+
+import "backend/internal/domain/entity"
+
+// nearestObjectKey picks the object to serve for a requested width: the
+// narrowest rendition at least that wide, or the original when none is.
+func (uc *ImageUseCase) nearestObjectKey(flyer *entity.Flyer, width int) string {
+	if width > 0 {
+		for _, r := range flyer.Renditions {
+			if r.Width >= width && r.Key != "" {
+				return r.Key
+			}
+		}
+	}
+	return uc.objectKey(flyer)
+}
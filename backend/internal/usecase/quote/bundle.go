@@ -0,0 +1,295 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	bundleVersion  = "1.0"
+	bundleFormat   = "JSON"
+	bundleEncoding = "UTF-8"
+	bundleFileType = "text"
+)
+
+// Bundle is the quotes catalog in the shape of
+// designs/quotesJSONStructure.json. It is exported and imported as is to
+// move quotes between environments.
+type Bundle struct {
+	Quotes   []entity.Quote `json:"quotes"`
+	Metadata BundleMetadata `json:"metadata"`
+}
+
+type BundleMetadata struct {
+	Version     string       `json:"version"`
+	LastUpdated string       `json:"lastUpdated"`
+	TotalQuotes int          `json:"totalQuotes"`
+	Url         string       `json:"url"`
+	Schema      BundleSchema `json:"schema"`
+}
+
+type BundleSchema struct {
+	Format   string `json:"format"`
+	Encoding string `json:"encoding"`
+	FileType string `json:"fileType"`
+}
+
+// BundleError lists every problem found in a bundle; nothing is imported.
+type BundleError struct {
+	Errors []FieldError
+}
+
+func (e *BundleError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "invalid bundle: " + strings.Join(msgs, "; ")
+}
+
+func (e *BundleError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// BundleResult counts what a bundle import did.
+type BundleResult struct {
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// ExportBundle returns every live quote with bundle metadata.
+func (uc *QuoteUseCase) ExportBundle() (*Bundle, error) {
+	quotes, err := uc.quoteRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quotes: %w", err)
+	}
+	if quotes == nil {
+		quotes = []entity.Quote{}
+	}
+	return &Bundle{
+		Quotes: quotes,
+		Metadata: BundleMetadata{
+			Version:     bundleVersion,
+			LastUpdated: time.Now().UTC().Format(time.RFC3339),
+			TotalQuotes: len(quotes),
+			Url:         os.Getenv("QUOTE_METADATA_URL"),
+			Schema: BundleSchema{
+				Format:   bundleFormat,
+				Encoding: bundleEncoding,
+				FileType: bundleFileType,
+			},
+		},
+	}, nil
+}
+
+// ImportBundle validates a bundle and upserts its quotes by ID in a single
+// transaction: quotes with a known ID, soft-deleted ones included, are
+// overwritten and the rest are created with their IDs. Quotes missing from
+// the bundle are left alone. A *BundleError lists every validation problem.
+func (uc *QuoteUseCase) ImportBundle(r io.Reader) (*BundleResult, error) {
+	bundle, err := decodeBundle(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	result := &BundleResult{Total: len(bundle.Quotes)}
+	err = uc.quoteRepo.Transaction(func(repo repository.QuoteRepository) error {
+		return upsertBundle(repo, bundle.Quotes, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Created+result.Updated > 0 {
+		if err := uc.updateMetadata(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// decodeBundle parses a bundle strictly: unknown fields and values of the
+// wrong type are errors.
+func decodeBundle(r io.Reader) (*Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var bundle Bundle
+	if err := dec.Decode(&bundle); err != nil {
+		field := "$"
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			field = typeErr.Field
+		}
+		return nil, &BundleError{Errors: []FieldError{{Field: field, Message: err.Error()}}}
+	}
+	if dec.More() {
+		return nil, &BundleError{Errors: []FieldError{{Field: "$", Message: "unexpected data after the bundle"}}}
+	}
+
+	// tell a missing quotes array apart from an empty one
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err == nil {
+		if _, ok := raw["quotes"]; !ok {
+			return nil, &BundleError{Errors: []FieldError{{Field: "quotes", Message: "is required"}}}
+		}
+	}
+	return &bundle, nil
+}
+
+func validateBundle(b *Bundle) error {
+	errs := &BundleError{}
+
+	meta := b.Metadata
+	if strings.TrimSpace(meta.Version) == "" {
+		errs.add("metadata.version", "is required")
+	}
+	if _, err := time.Parse(time.RFC3339, meta.LastUpdated); err != nil {
+		errs.add("metadata.lastUpdated", "must be an RFC 3339 timestamp")
+	}
+	if meta.TotalQuotes != len(b.Quotes) {
+		errs.add("metadata.totalQuotes", "is %d but the bundle has %d quotes", meta.TotalQuotes, len(b.Quotes))
+	}
+	if !strings.EqualFold(meta.Schema.Format, bundleFormat) {
+		errs.add("metadata.schema.format", "must be %q", bundleFormat)
+	}
+	if !strings.EqualFold(meta.Schema.Encoding, bundleEncoding) {
+		errs.add("metadata.schema.encoding", "must be %q", bundleEncoding)
+	}
+
+	ids := make(map[int]int, len(b.Quotes))
+	keys := make(map[string]int, len(b.Quotes))
+	for i := range b.Quotes {
+		q := &b.Quotes[i]
+		field := fmt.Sprintf("quotes[%d]", i)
+
+		if q.Id <= 0 {
+			errs.add(field+".id", "must be a positive integer")
+		} else if first, ok := ids[q.Id]; ok {
+			errs.add(field+".id", "repeats the ID of quotes[%d]", first)
+		} else {
+			ids[q.Id] = i
+		}
+		var validationErr *ValidationError
+		if err := ValidateQuote(q); errors.As(err, &validationErr) {
+			for _, fe := range validationErr.Errors {
+				fe.Field = field + "." + fe.Field
+				errs.Errors = append(errs.Errors, fe)
+			}
+		}
+		if q.Text != "" {
+			key := naturalKey(&entity.Quote{Text: q.Text, Lang: q.Lang}, "")
+			if first, ok := keys[key]; ok {
+				errs.add(field+".text", "duplicates quotes[%d]", first)
+			} else {
+				keys[key] = i
+			}
+		}
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// upsertBundle writes the bundle's quotes through repo, counting outcomes
+// in result.
+func upsertBundle(repo repository.QuoteRepository, quotes []entity.Quote, result *BundleResult) error {
+	ids := make([]int, len(quotes))
+	for i, q := range quotes {
+		ids[i] = q.Id
+	}
+	stored, err := repo.FindByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("failed to load quotes: %w", err)
+	}
+	byID := make(map[int]entity.Quote, len(stored))
+	for _, q := range stored {
+		byID[q.Id] = q
+	}
+
+	prepared := make([]entity.Quote, len(quotes))
+	keys := make([]string, len(quotes))
+	for i, in := range quotes {
+		q := entity.Quote{Id: in.Id, Version: 1}
+		if old, ok := byID[in.Id]; ok {
+			q = old
+		}
+		q.Text, q.Lang, q.Author = in.Text, in.Lang, in.Author
+		q.Work, q.Year, q.License = in.Work, in.Year, in.License
+		q.AttributionRequired = in.AttributionRequired
+		q.Tags = normalizeTags(in.Tags)
+		q.ExternalId, q.TranslationGroup = in.ExternalId, in.TranslationGroup
+		q.NaturalKey = naturalKey(&q, q.ImportSource)
+		setFingerprint(&q)
+		prepared[i], keys[i] = q, q.NaturalKey
+	}
+
+	// a quote outside the bundle with the same text and language would
+	// break the natural key index
+	clashes, err := repo.FindByNaturalKeys(keys)
+	if err != nil {
+		return fmt.Errorf("failed to look up quotes: %w", err)
+	}
+	bundled := make(map[int]bool, len(quotes))
+	for _, q := range quotes {
+		bundled[q.Id] = true
+	}
+	for _, other := range clashes {
+		if !bundled[other.Id] {
+			return fmt.Errorf("%w: quote %d has the same text and language as a bundled quote", ErrDuplicateQuote, other.Id)
+		}
+	}
+
+	var creates []entity.Quote
+	for _, q := range prepared {
+		old, exists := byID[q.Id]
+		switch {
+		case !exists:
+			creates = append(creates, q)
+			result.Created++
+		case old.DeletedAt.Valid:
+			if err := repo.Restore(q.Id); err != nil {
+				return fmt.Errorf("failed to restore quote %d: %w", q.Id, err)
+			}
+			q.DeletedAt = gorm.DeletedAt{}
+			fallthrough
+		case !sameQuote(old, q):
+			if err := repo.UpdateVersion(&q, old.Version); err != nil {
+				return fmt.Errorf("failed to update quote %d: %w", q.Id, err)
+			}
+			result.Updated++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if err := repo.StoreBatch(creates); err != nil {
+		return fmt.Errorf("failed to insert quotes: %w", err)
+	}
+	if len(creates) > 0 {
+		if err := repo.SyncIDSequence(); err != nil {
+			return fmt.Errorf("failed to advance quote IDs: %w", err)
+		}
+	}
+	return nil
+}
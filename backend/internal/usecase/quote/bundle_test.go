@@ -0,0 +1,110 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	source := NewQuoteUseCase(&fakeQuoteRepo{}, nil, nil, &fakeMetadata{})
+	for _, text := range []string{"Be kind.", "Stay curious.", "Keep going."} {
+		if _, err := source.CreateQuote(&entity.Quote{Text: text, Tags: []string{"life"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := source.DeleteQuote(2, 0); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := source.ExportBundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Metadata.TotalQuotes != 2 || bundle.Metadata.Schema.Format != "JSON" {
+		t.Fatalf("unexpected bundle metadata: %+v", bundle.Metadata)
+	}
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the target already has quote 1 with other text
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	target := NewQuoteUseCase(repo, nil, nil, meta)
+	if _, err := target.CreateQuote(&entity.Quote{Text: "Old text."}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := target.ImportBundle(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Total != 2 || result.Created != 1 || result.Updated != 1 || meta.quoteUpdates != 2 {
+		t.Errorf("unexpected result: %+v, metadata updates %d", result, meta.quoteUpdates)
+	}
+	third, err := target.GetQuote(3)
+	if err != nil || third.Text != "Keep going." {
+		t.Errorf("expected quote 3 to keep its ID, got %+v, %v", third, err)
+	}
+	first, _ := target.GetQuote(1)
+	if first.Text != "Be kind." || first.Version != 2 {
+		t.Errorf("expected quote 1 to be overwritten, got %+v", first)
+	}
+
+	result, err = target.ImportBundle(bytes.NewReader(data))
+	if err != nil || result.Unchanged != 2 || meta.quoteUpdates != 2 {
+		t.Errorf("expected a repeat import to change nothing, got %+v, %v", result, err)
+	}
+}
+
+func TestImportBundleValidation(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+
+	body := `{
+		"quotes": [
+			{"id": 1, "text": "Be kind.", "tags": ["life"], "lang": "en-US"},
+			{"id": 1, "text": " ", "tags": ["#life"], "lang": "english"}
+		],
+		"metadata": {
+			"version": "1.0",
+			"lastUpdated": "yesterday",
+			"totalQuotes": 3,
+			"url": "",
+			"schema": {"format": "JSON", "encoding": "UTF-8", "fileType": "text"}
+		}
+	}`
+	_, err := uc.ImportBundle(strings.NewReader(body))
+	var bundleErr *BundleError
+	if !errors.As(err, &bundleErr) {
+		t.Fatalf("expected a bundle error, got %v", err)
+	}
+	want := []string{
+		"metadata.lastUpdated", "metadata.totalQuotes",
+		"quotes[1].id", "quotes[1].text", "quotes[1].tags[0]", "quotes[1].lang",
+	}
+	if len(bundleErr.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), bundleErr.Errors)
+	}
+	for i, field := range want {
+		if bundleErr.Errors[i].Field != field {
+			t.Errorf("error %d: expected field %s, got %+v", i, field, bundleErr.Errors[i])
+		}
+	}
+	if len(repo.quotes) != 0 {
+		t.Errorf("expected nothing to be stored, got %d quotes", len(repo.quotes))
+	}
+
+	for _, body := range []string{
+		`{"metadata": {}}`,
+		`{"quotes": [], "metadata": {}, "extra": true}`,
+		`{"quotes": [{"id": "one"}], "metadata": {}}`,
+	} {
+		if _, err := uc.ImportBundle(strings.NewReader(body)); !errors.As(err, &bundleErr) {
+			t.Errorf("%s: expected a bundle error, got %v", body, err)
+		}
+	}
+}
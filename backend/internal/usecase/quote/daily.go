@@ -0,0 +1,383 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	defaultDailyWindow = 30
+	maxAutoFillDays    = 366
+)
+
+// ErrInvalidDailyQuote is returned when a schedule entry or auto-fill request
+// fails validation.
+var ErrInvalidDailyQuote = errors.New("invalid daily quote")
+
+// ErrDailyQuoteTaken is returned when a date and locale already have a quote.
+var ErrDailyQuoteTaken = errors.New("date already scheduled")
+
+// AutoFillRequest asks for the days from From on to be filled for Locale.
+// Dates that already have a quote are kept. Tags is the rotation: each date
+// is given a quote carrying the tag whose index is the date's day number
+// modulo len(Tags), falling through to the next tags when that one has no
+// unused quote left. Without tags any unused quote is taken.
+type AutoFillRequest struct {
+	From   string   `json:"from"`
+	Days   int      `json:"days"`
+	Locale string   `json:"locale"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// AutoFillResult lists the entries auto-fill created and the dates it could
+// not fill because no unused quote was left.
+type AutoFillResult struct {
+	Created  []entity.DailyQuote `json:"created"`
+	Unfilled []string            `json:"unfilled"`
+}
+
+// DailyUseCase manages the quote of the day schedule and publishes the
+// upcoming window of it as dailyQuotes.json.
+type DailyUseCase struct {
+	quotes *QuoteUseCase
+	days   repository.DailyQuoteRepository
+	// window is how many days, today included, the published file covers.
+	window int
+	now    func() time.Time
+}
+
+func NewDailyUseCase(quotes *QuoteUseCase, days repository.DailyQuoteRepository, window int) *DailyUseCase {
+	if window <= 0 {
+		window = defaultDailyWindow
+	}
+	return &DailyUseCase{
+		quotes: quotes,
+		days:   days,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// ListDays returns the entries from one date to another, inclusive. from
+// defaults to today and to to the end of the published window.
+func (uc *DailyUseCase) ListDays(from, to, locale string) ([]entity.DailyQuote, error) {
+	today := uc.today()
+	if from == "" {
+		from = today.Format(dateLayout)
+	}
+	if to == "" {
+		to = today.AddDate(0, 0, uc.window-1).Format(dateLayout)
+	}
+	if _, err := parseDate(from); err != nil {
+		return nil, err
+	}
+	if _, err := parseDate(to); err != nil {
+		return nil, err
+	}
+	return uc.days.FindRange(from, to, strings.TrimSpace(locale))
+}
+
+// GetDay returns one schedule entry, or repository.ErrNotFound.
+func (uc *DailyUseCase) GetDay(id int) (*entity.DailyQuote, error) {
+	return uc.days.FindByID(id)
+}
+
+// CreateDay schedules a quote for a date and locale and republishes the
+// schedule. Any client supplied ID is ignored.
+func (uc *DailyUseCase) CreateDay(day *entity.DailyQuote) error {
+	day.Id = 0
+	day.AutoFilled, day.Tag = false, ""
+	if err := uc.validate(day); err != nil {
+		return err
+	}
+
+	id, err := uc.days.Store(day)
+	if err != nil {
+		return fmt.Errorf("failed to store daily quote: %w", err)
+	}
+	day.Id = id
+	return uc.Publish()
+}
+
+// UpdateDay replaces the date, locale and quote of an entry. The entry
+// counts as an editor's pick afterwards.
+func (uc *DailyUseCase) UpdateDay(id int, replacement entity.DailyQuote) (*entity.DailyQuote, error) {
+	day, err := uc.days.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	day.Date = replacement.Date
+	day.Locale = replacement.Locale
+	day.QuoteId = replacement.QuoteId
+	day.AutoFilled, day.Tag = false, ""
+	if err := uc.validate(day); err != nil {
+		return nil, err
+	}
+
+	if err := uc.days.Update(day); err != nil {
+		return nil, fmt.Errorf("failed to update daily quote: %w", err)
+	}
+	return day, uc.Publish()
+}
+
+// DeleteDay removes an entry and republishes the schedule.
+func (uc *DailyUseCase) DeleteDay(id int) error {
+	if err := uc.days.Delete(id); err != nil {
+		return err
+	}
+	return uc.Publish()
+}
+
+// validate normalises day and checks that its date is valid, its quote
+// exists in its locale and no other entry holds the same date and locale.
+func (uc *DailyUseCase) validate(day *entity.DailyQuote) error {
+	date, err := parseDate(day.Date)
+	if err != nil {
+		return err
+	}
+	day.Date = date.Format(dateLayout)
+	day.Locale = strings.TrimSpace(day.Locale)
+	if day.Locale == "" {
+		day.Locale = defaultLang
+	}
+	if day.QuoteId <= 0 {
+		return fmt.Errorf("%w: quoteId is required", ErrInvalidDailyQuote)
+	}
+
+	q, err := uc.quotes.quoteRepo.FindByID(day.QuoteId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: quote %d not found", ErrInvalidDailyQuote, day.QuoteId)
+		}
+		return err
+	}
+	if !strings.EqualFold(q.Lang, day.Locale) {
+		return fmt.Errorf("%w: quote %d is in %s, not %s", ErrInvalidDailyQuote, q.Id, q.Lang, day.Locale)
+	}
+
+	taken, err := uc.days.FindRange(day.Date, day.Date, day.Locale)
+	if err != nil {
+		return err
+	}
+	for _, other := range taken {
+		if other.Id != day.Id {
+			return fmt.Errorf("%w: %s in %s has entry %d", ErrDailyQuoteTaken, day.Date, day.Locale, other.Id)
+		}
+	}
+	return nil
+}
+
+// AutoFill fills the unscheduled days of the request with quotes never
+// scheduled before in the locale, following the tag rotation, and
+// republishes the schedule.
+func (uc *DailyUseCase) AutoFill(req AutoFillRequest) (*AutoFillResult, error) {
+	from := uc.today()
+	if req.From != "" {
+		d, err := parseDate(req.From)
+		if err != nil {
+			return nil, err
+		}
+		from = d
+	}
+	if req.Days <= 0 {
+		req.Days = uc.window
+	}
+	if req.Days > maxAutoFillDays {
+		return nil, fmt.Errorf("%w: days must be at most %d", ErrInvalidDailyQuote, maxAutoFillDays)
+	}
+	req.Locale = strings.TrimSpace(req.Locale)
+	if req.Locale == "" {
+		req.Locale = defaultLang
+	}
+	rotation := normalizeTags(req.Tags)
+
+	to := from.AddDate(0, 0, req.Days-1)
+	existing, err := uc.days.FindRange(from.Format(dateLayout), to.Format(dateLayout), req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := make(map[string]bool, len(existing))
+	for _, day := range existing {
+		scheduled[day.Date] = true
+	}
+
+	picker, err := uc.newPicker(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AutoFillResult{Created: []entity.DailyQuote{}, Unfilled: []string{}}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format(dateLayout)
+		if scheduled[date] {
+			continue
+		}
+		q, tag := picker.pick(rotation, dayNumber(d))
+		if q == nil {
+			result.Unfilled = append(result.Unfilled, date)
+			continue
+		}
+		day := entity.DailyQuote{Date: date, Locale: req.Locale, QuoteId: q.Id, AutoFilled: true, Tag: tag}
+		id, err := uc.days.Store(&day)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store daily quote for %s: %w", date, err)
+		}
+		day.Id = id
+		result.Created = append(result.Created, day)
+	}
+
+	if len(result.Created) > 0 {
+		if err := uc.Publish(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Publish uploads the schedule for the next window days, each entry with
+// its quote.
+func (uc *DailyUseCase) Publish() error {
+	today := uc.today()
+	days, err := uc.days.FindRange(today.Format(dateLayout), today.AddDate(0, 0, uc.window-1).Format(dateLayout), "")
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(days))
+	for _, day := range days {
+		ids = append(ids, day.QuoteId)
+	}
+	quotes, err := uc.quotes.quoteRepo.FindByIDs(ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]*entity.Quote, len(quotes))
+	for i := range quotes {
+		if !quotes[i].DeletedAt.Valid {
+			byID[quotes[i].Id] = &quotes[i]
+		}
+	}
+
+	published := days[:0]
+	for _, day := range days {
+		// entries whose quote was deleted since are left out
+		if q, ok := byID[day.QuoteId]; ok {
+			day.Quote = q
+			published = append(published, day)
+		}
+	}
+	return uc.quotes.metadataService.UpdateDailyQuoteMetadata(published)
+}
+
+// Start republishes the schedule every interval until ctx is cancelled, so
+// the published window moves forward with the calendar.
+func (uc *DailyUseCase) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.Publish(); err != nil {
+			log.Printf("Daily quotes publish: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *DailyUseCase) today() time.Time {
+	now := uc.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// quotePicker hands out the quotes of one locale that were never scheduled,
+// lowest ID first, each at most once.
+type quotePicker struct {
+	candidates []entity.Quote
+	used       map[int]bool
+}
+
+func (uc *DailyUseCase) newPicker(locale string) (*quotePicker, error) {
+	all, err := uc.quotes.quoteRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	usedIDs, err := uc.days.UsedQuoteIDs(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &quotePicker{used: make(map[int]bool, len(usedIDs))}
+	for _, id := range usedIDs {
+		p.used[id] = true
+	}
+	for _, q := range all {
+		if strings.EqualFold(q.Lang, locale) && !p.used[q.Id] {
+			p.candidates = append(p.candidates, q)
+		}
+	}
+	sort.Slice(p.candidates, func(i, j int) bool { return p.candidates[i].Id < p.candidates[j].Id })
+	return p, nil
+}
+
+// pick takes the next unused quote for day n of the rotation, trying the
+// following tags in turn when the due one is exhausted. It returns the tag
+// the quote was picked for, or a nil quote when nothing is left.
+func (p *quotePicker) pick(rotation []string, n int) (*entity.Quote, string) {
+	if len(rotation) == 0 {
+		return p.take(""), ""
+	}
+	for i := range rotation {
+		tag := rotation[(n+i)%len(rotation)]
+		if q := p.take(tag); q != nil {
+			return q, tag
+		}
+	}
+	return nil, ""
+}
+
+func (p *quotePicker) take(tag string) *entity.Quote {
+	for i := range p.candidates {
+		q := &p.candidates[i]
+		if p.used[q.Id] || (tag != "" && !containsFold(q.Tags, tag)) {
+			continue
+		}
+		p.used[q.Id] = true
+		return q
+	}
+	return nil
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// dayNumber counts days since the Unix epoch, so a date always falls on the
+// same step of a rotation whichever range it is filled in.
+func dayNumber(d time.Time) int {
+	return int(d.Unix() / 86400)
+}
+
+func parseDate(value string) (time.Time, error) {
+	d, err := time.Parse(dateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date %q must look like 2024-04-01", ErrInvalidDailyQuote, value)
+	}
+	return d, nil
+}
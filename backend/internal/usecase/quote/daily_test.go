@@ -0,0 +1,167 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeDailyRepo struct {
+	days map[int]*entity.DailyQuote
+	next int
+}
+
+func (r *fakeDailyRepo) Store(d *entity.DailyQuote) (int, error) {
+	r.next++
+	d.Id = r.next
+	copied := *d
+	r.days[d.Id] = &copied
+	return d.Id, nil
+}
+
+func (r *fakeDailyRepo) Update(d *entity.DailyQuote) error {
+	copied := *d
+	r.days[d.Id] = &copied
+	return nil
+}
+
+func (r *fakeDailyRepo) Delete(id int) error {
+	if _, ok := r.days[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.days, id)
+	return nil
+}
+
+func (r *fakeDailyRepo) FindByID(id int) (*entity.DailyQuote, error) {
+	d, ok := r.days[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (r *fakeDailyRepo) FindRange(from, to, locale string) ([]entity.DailyQuote, error) {
+	var found []entity.DailyQuote
+	for _, d := range r.days {
+		if d.Date >= from && d.Date <= to && (locale == "" || strings.EqualFold(d.Locale, locale)) {
+			found = append(found, *d)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Date < found[j].Date })
+	return found, nil
+}
+
+func (r *fakeDailyRepo) UsedQuoteIDs(locale string) ([]int, error) {
+	var ids []int
+	for _, d := range r.days {
+		if strings.EqualFold(d.Locale, locale) {
+			ids = append(ids, d.QuoteId)
+		}
+	}
+	return ids, nil
+}
+
+func newDailyFixture(t *testing.T, quotes ...entity.Quote) (*DailyUseCase, *fakeDailyRepo, *fakeMetadata) {
+	t.Helper()
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	for i := range quotes {
+		if _, err := repo.Store(&quotes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	days := &fakeDailyRepo{days: map[int]*entity.DailyQuote{}}
+	uc := NewDailyUseCase(NewQuoteUseCase(repo, nil, nil, meta), days, 7)
+	uc.now = func() time.Time { return time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC) }
+	return uc, days, meta
+}
+
+func TestDailyCreateValidates(t *testing.T) {
+	uc, _, meta := newDailyFixture(t,
+		entity.Quote{Text: "Be kind.", Lang: "en-US"},
+		entity.Quote{Text: "Sois gentil.", Lang: "fr-FR"},
+	)
+
+	day := &entity.DailyQuote{Date: "2024-05-02", Locale: "en-us", QuoteId: 1}
+	if err := uc.CreateDay(day); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(meta.daily) != 1 || meta.daily[0].Quote == nil || meta.daily[0].Quote.Text != "Be kind." {
+		t.Errorf("expected the schedule to be published with its quote, got %+v", meta.daily)
+	}
+
+	cases := []struct {
+		day  entity.DailyQuote
+		want error
+	}{
+		{entity.DailyQuote{Date: "2024-05-02", Locale: "en-US", QuoteId: 1}, ErrDailyQuoteTaken},
+		{entity.DailyQuote{Date: "05/03/2024", QuoteId: 1}, ErrInvalidDailyQuote},
+		{entity.DailyQuote{Date: "2024-05-03", Locale: "en-US", QuoteId: 2}, ErrInvalidDailyQuote},
+		{entity.DailyQuote{Date: "2024-05-03", Locale: "en-US", QuoteId: 9}, ErrInvalidDailyQuote},
+	}
+	for _, c := range cases {
+		if err := uc.CreateDay(&c.day); !errors.Is(err, c.want) {
+			t.Errorf("%+v: expected %v, got %v", c.day, c.want, err)
+		}
+	}
+}
+
+func TestDailyAutoFillRotatesTags(t *testing.T) {
+	uc, days, meta := newDailyFixture(t,
+		entity.Quote{Text: "Love one.", Lang: "en-US", Tags: []string{"love"}},
+		entity.Quote{Text: "Work one.", Lang: "en-US", Tags: []string{"work"}},
+		entity.Quote{Text: "Love two.", Lang: "en-US", Tags: []string{"Love"}},
+		entity.Quote{Text: "Work two.", Lang: "en-US", Tags: []string{"work"}},
+		entity.Quote{Text: "Amour.", Lang: "fr-FR", Tags: []string{"love"}},
+	)
+	// 2024-05-01 is day 19844, an even day, so the rotation starts on love.
+	if err := uc.CreateDay(&entity.DailyQuote{Date: "2024-05-02", Locale: "en-US", QuoteId: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := uc.AutoFill(AutoFillRequest{Days: 5, Tags: []string{"love", "work"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var got []string
+	for _, d := range result.Created {
+		got = append(got, d.Date+":"+d.Tag)
+		if !d.AutoFilled || d.Locale != defaultLang {
+			t.Errorf("unexpected entry %+v", d)
+		}
+	}
+	// 05-02 is taken and quote 2 used, so 05-03 (love) gets quote 3, 05-04
+	// (work) gets quote 4 and nothing is left for 05-05.
+	if strings.Join(got, ",") != "2024-05-01:love,2024-05-03:love,2024-05-04:work" {
+		t.Errorf("unexpected auto-fill: %v", got)
+	}
+	if len(result.Unfilled) != 1 || result.Unfilled[0] != "2024-05-05" {
+		t.Errorf("expected 2024-05-05 to stay unfilled, got %v", result.Unfilled)
+	}
+	if result.Created[1].QuoteId != 3 || result.Created[2].QuoteId != 4 {
+		t.Errorf("expected unused quotes 3 and 4, got %+v", result.Created)
+	}
+	if len(days.days) != 4 || len(meta.daily) != 4 {
+		t.Errorf("expected 4 scheduled and published days, got %d and %d", len(days.days), len(meta.daily))
+	}
+}
+
+func TestDailyPublishCoversWindow(t *testing.T) {
+	uc, days, meta := newDailyFixture(t, entity.Quote{Text: "Be kind.", Lang: "en-US"})
+	for _, date := range []string{"2024-04-30", "2024-05-01", "2024-05-07", "2024-05-08"} {
+		days.Store(&entity.DailyQuote{Date: date, Locale: "en-US", QuoteId: 1})
+	}
+
+	if err := uc.Publish(); err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.daily) != 2 || meta.daily[0].Date != "2024-05-01" || meta.daily[1].Date != "2024-05-07" {
+		t.Errorf("expected today through six days ahead, got %+v", meta.daily)
+	}
+}
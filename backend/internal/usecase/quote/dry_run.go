@@ -0,0 +1,108 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"fmt"
+	"strings"
+)
+
+// ImportDiff lists what a dry-run import would do. Skip covers unchanged
+// quotes as well as rejected rows.
+type ImportDiff struct {
+	Create []DiffEntry `json:"create"`
+	Update []DiffEntry `json:"update"`
+	Skip   []DiffEntry `json:"skip"`
+	Delete []DiffEntry `json:"delete"`
+}
+
+// DiffEntry describes one quote in an ImportDiff. Id is the stored quote an
+// update, skip or delete applies to.
+type DiffEntry struct {
+	Sheet  string   `json:"sheet,omitempty"`
+	Row    int      `json:"row,omitempty"`
+	Id     int      `json:"id,omitempty"`
+	Text   string   `json:"text,omitempty"`
+	Lang   string   `json:"lang,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// planBatch records what writeBatch would do with the rows without writing
+// them.
+func (imp *importer) planBatch(batch []importRow) {
+	if imp.err != nil {
+		return
+	}
+	keys := make([]string, len(batch))
+	for i, r := range batch {
+		keys[i] = r.quote.NaturalKey
+	}
+	existing, err := imp.repo.FindByNaturalKeys(keys)
+	if err != nil {
+		imp.err = fmt.Errorf("failed to look up quotes: %w", err)
+		return
+	}
+	byKey := make(map[string]entity.Quote, len(existing))
+	for _, q := range existing {
+		byKey[q.NaturalKey] = q
+	}
+
+	diff := imp.result.Diff
+	for _, r := range batch {
+		entry := DiffEntry{Sheet: r.sheet, Row: r.row, Text: r.quote.Text, Lang: r.quote.Lang, Tags: r.quote.Tags}
+		old, ok := byKey[r.quote.NaturalKey]
+		if !ok {
+			imp.result.Created++
+			diff.Create = append(diff.Create, entry)
+			continue
+		}
+
+		entry.Id = old.Id
+		if changed := changedFields(old, imp.incoming(old, r.quote)); len(changed) > 0 {
+			entry.Reason = "changed " + strings.Join(changed, ", ")
+			imp.result.Updated++
+			diff.Update = append(diff.Update, entry)
+			continue
+		}
+		entry.Reason = "unchanged"
+		imp.result.Unchanged++
+		diff.Skip = append(diff.Skip, entry)
+	}
+}
+
+// planRollback reports that an all-or-nothing import would store nothing,
+// keeping only the skipped rows in the diff.
+func (imp *importer) planRollback() {
+	imp.result.RolledBack = true
+	imp.result.Created, imp.result.Updated, imp.result.Unchanged = 0, 0, 0
+	imp.result.Diff.Create, imp.result.Diff.Update = nil, nil
+}
+
+// planMirror lists the quotes of the source a mirror import would delete.
+func (imp *importer) planMirror() {
+	if !imp.opts.Mirror {
+		return
+	}
+	if imp.incomplete {
+		imp.result.MirrorSkipped = "some rows or sheets could not be imported, so no quotes would be deleted"
+		return
+	}
+	stored, err := imp.repo.FindBySource(imp.opts.Source)
+	if err != nil {
+		imp.err = fmt.Errorf("failed to look up quotes of %q: %w", imp.opts.Source, err)
+		return
+	}
+	for _, q := range stored {
+		if _, ok := imp.seen[q.NaturalKey]; ok {
+			continue
+		}
+		imp.result.Deleted++
+		imp.result.Diff.Delete = append(imp.result.Diff.Delete, DiffEntry{
+			Id:     q.Id,
+			Text:   q.Text,
+			Lang:   q.Lang,
+			Tags:   q.Tags,
+			Reason: "no longer in source",
+		})
+	}
+}
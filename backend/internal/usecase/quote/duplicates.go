@@ -0,0 +1,175 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"fmt"
+	"sort"
+)
+
+// maxReportedDuplicates caps the near duplicates listed for very large
+// imports; ImportResult.NearDuplicateCount still counts every one.
+const maxReportedDuplicates = 1000
+
+// NearDuplicate flags an imported row whose text closely resembles a stored
+// quote (Id set) or an earlier row of the same import (Of set).
+type NearDuplicate struct {
+	Sheet      string  `json:"sheet,omitempty"`
+	Row        int     `json:"row"`
+	Text       string  `json:"text"`
+	Id         int     `json:"id,omitempty"`
+	Of         string  `json:"of,omitempty"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster groups quotes that are near duplicates of each other,
+// directly or through another member. Similarity is the weakest link that
+// joined the cluster.
+type DuplicateCluster struct {
+	Quotes     []entity.Quote `json:"quotes"`
+	Similarity float64        `json:"similarity"`
+}
+
+// FindDuplicates groups the live quotes into clusters of near duplicates in
+// the same language, largest first. A threshold of 0 uses
+// QUOTE_DUPLICATE_THRESHOLD.
+func (uc *QuoteUseCase) FindDuplicates(threshold float64) ([]DuplicateCluster, error) {
+	if threshold == 0 {
+		threshold = duplicateThreshold()
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("%w: threshold must be between 0 and 1", ErrInvalidQuote)
+	}
+
+	quotes, err := uc.quoteRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quotes: %w", err)
+	}
+
+	index := newDupIndex(threshold)
+	parent := make([]int, len(quotes))
+	weakest := make([]float64, len(quotes))
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range quotes {
+		parent[i], weakest[i] = i, 1
+		sig := signature(&quotes[i])
+		for _, j := range index.candidates(sig) {
+			other := index.entries[j]
+			if other.lang != quotes[i].Lang {
+				continue
+			}
+			sim := similarity(sig, other.sig)
+			if sim < threshold {
+				continue
+			}
+			a, b := find(i), find(other.id)
+			if a != b {
+				parent[a] = b
+				weakest[b] = minFloat(weakest[a], weakest[b], sim)
+			}
+		}
+		// entries carry the slice index in id so links map back to quotes
+		index.insert(dupEntry{id: i, lang: quotes[i].Lang, sig: sig})
+	}
+
+	members := make(map[int][]entity.Quote)
+	for i := range quotes {
+		root := find(i)
+		members[root] = append(members[root], quotes[i])
+	}
+	clusters := []DuplicateCluster{}
+	for root, group := range members {
+		if len(group) > 1 {
+			clusters = append(clusters, DuplicateCluster{Quotes: group, Similarity: weakest[root]})
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Quotes) != len(clusters[j].Quotes) {
+			return len(clusters[i].Quotes) > len(clusters[j].Quotes)
+		}
+		return clusters[i].Quotes[0].Id < clusters[j].Quotes[0].Id
+	})
+	return clusters, nil
+}
+
+// flagNearDuplicates reports the rows of a batch that resemble a stored
+// quote or an earlier row of the batch. Only the stored quotes sharing a
+// band hash with the batch are loaded. Earlier batches are stored by then,
+// so their rows are reported as stored quotes, except in dry runs, which
+// compare rows within a batch only.
+func (imp *importer) flagNearDuplicates(batch []importRow) error {
+	var bands []int64
+	var langs []string
+	for _, r := range batch {
+		bands = append(bands, r.quote.Bands...)
+		if !containsFold(langs, r.quote.Lang) {
+			langs = append(langs, r.quote.Lang)
+		}
+	}
+	stored, err := imp.repo.FindByBands(bands, langs)
+	if err != nil {
+		return fmt.Errorf("failed to look up near duplicates: %w", err)
+	}
+
+	index := newDupIndex(imp.dupThreshold)
+	for i := range stored {
+		q := &stored[i]
+		index.insert(dupEntry{id: q.Id, key: q.NaturalKey, lang: q.Lang, sig: signature(q)})
+	}
+	for _, r := range batch {
+		q := &r.quote
+		if match, sim, ok := index.match(q.Fingerprint, q.Lang, q.NaturalKey); ok {
+			imp.result.NearDuplicateCount++
+			if len(imp.result.NearDuplicates) < maxReportedDuplicates {
+				imp.result.NearDuplicates = append(imp.result.NearDuplicates, NearDuplicate{
+					Sheet:      r.sheet,
+					Row:        r.row,
+					Text:       q.Text,
+					Id:         match.id,
+					Of:         match.label,
+					Similarity: sim,
+				})
+			}
+		}
+		index.insert(dupEntry{label: rowLabel(r.sheet, r.row), key: q.NaturalKey, lang: q.Lang, sig: q.Fingerprint})
+	}
+	return nil
+}
+
+// BackfillFingerprints computes the fingerprints and band hashes of the
+// quotes stored before band hashes were, a batch at a time, so the import
+// near-duplicate check finds them.
+func (uc *QuoteUseCase) BackfillFingerprints() error {
+	size := batchSize()
+	for {
+		quotes, err := uc.quoteRepo.FindUnbanded(size)
+		if err != nil {
+			return fmt.Errorf("failed to load quotes without band hashes: %w", err)
+		}
+		for i := range quotes {
+			setFingerprint(&quotes[i])
+		}
+		if err := uc.quoteRepo.SetFingerprints(quotes); err != nil {
+			return fmt.Errorf("failed to store band hashes: %w", err)
+		}
+		if len(quotes) < size {
+			return nil
+		}
+	}
+}
+
+func minFloat(values ...float64) float64 {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,120 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFoldText(t *testing.T) {
+	cases := map[string]string{
+		"“Be kind,” always.":          "be kind always",
+		"\"Be kind,\"   always!":      "be kind always",
+		"Cafe\u0301 culture.":         "caf\u00e9 culture",
+		"Know thyself. — Socrates":    "know thyself",
+		"Know thyself - Socrates":     "know thyself",
+		"It was well - known in town": "it was well known in town",
+		"Don’t panic.":                "don t panic",
+	}
+	for in, want := range cases {
+		if got := foldText(in); got != want {
+			t.Errorf("foldText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFingerprintSimilarity(t *testing.T) {
+	a := fingerprint("The only way to do great work is to love what you do.")
+	b := fingerprint("“The only way to do great work is to love what you do” — Steve Jobs")
+	c := fingerprint("Simplicity is the ultimate sophistication.")
+	if sim := similarity(a, b); sim != 1 {
+		t.Errorf("expected punctuation and attribution to fold away, got %.2f", sim)
+	}
+	if sim := similarity(a, fingerprint("The only way to do great work is to love what you make.")); sim < 0.6 || sim == 1 {
+		t.Errorf("expected a close but inexact match, got %.2f", sim)
+	}
+	if sim := similarity(a, c); sim > 0.2 {
+		t.Errorf("expected unrelated quotes to differ, got %.2f", sim)
+	}
+}
+
+func TestImportFlagsNearDuplicates(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	stored, err := uc.CreateQuote(&entity.Quote{Text: "Stay hungry, stay foolish."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := "text,lang\n" +
+		"\"“Stay hungry; stay foolish” — Steve Jobs\",en-US\n" +
+		"Simplicity is the ultimate sophistication.,en-US\n" +
+		"Simplicity is the ultimate sophistication!!,en-US\n" +
+		"\"Stay hungry, stay foolish.\",fr-FR\n"
+	result, err := uc.ImportStream(strings.NewReader(body), StreamOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 4 {
+		t.Errorf("expected near duplicates to be imported anyway, got %+v", result)
+	}
+	if len(result.NearDuplicates) != 2 {
+		t.Fatalf("expected 2 near duplicates, got %+v", result.NearDuplicates)
+	}
+	if d := result.NearDuplicates[0]; d.Row != 2 || d.Id != stored.Id {
+		t.Errorf("expected row 2 to match quote %d, got %+v", stored.Id, d)
+	}
+	if d := result.NearDuplicates[1]; d.Row != 4 || d.Of != "row 3" {
+		t.Errorf("expected row 4 to match row 3, got %+v", d)
+	}
+
+	clusters, err := uc.FindDuplicates(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 || len(clusters[0].Quotes) != 2 || len(clusters[1].Quotes) != 2 {
+		t.Fatalf("expected two pairs, got %+v", clusters)
+	}
+	if clusters[0].Quotes[0].Id != stored.Id || clusters[0].Similarity < defaultDuplicateThreshold {
+		t.Errorf("unexpected first cluster: %+v", clusters[0])
+	}
+}
+
+func TestImportNearDuplicatesPerBatch(t *testing.T) {
+	t.Setenv("BATCH_SIZE_QUOTE", "2")
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	// stored before band hashes existed
+	repo.quotes = []entity.Quote{{Id: 1, Text: "Stay hungry, stay foolish.", Lang: "en-US", Version: 1}}
+	if err := uc.BackfillFingerprints(); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.quotes[0].Bands) != fingerprintBands {
+		t.Fatalf("expected the backfill to store band hashes, got %v", repo.quotes[0].Bands)
+	}
+
+	body := "text\n" +
+		"Stay hungry; stay foolish!\n" +
+		"Simplicity is the ultimate sophistication.\n" +
+		"Simplicity is the ultimate sophistication!!\n"
+	result, err := uc.ImportStream(strings.NewReader(body), StreamOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.bandLookups != 2 {
+		t.Errorf("expected one candidate lookup per batch, got %d", repo.bandLookups)
+	}
+	// row 4 is in the second batch, so it matches the quote row 3 became
+	if result.NearDuplicateCount != 2 || result.NearDuplicates[0].Id != 1 || result.NearDuplicates[1].Id != 3 {
+		t.Errorf("unexpected near duplicates: %+v", result.NearDuplicates)
+	}
+
+	repo.bandErr = errors.New("connection lost")
+	if _, err := uc.ImportStream(strings.NewReader("text\nOne more.\n"), StreamOptions{Format: FormatCSV}); err == nil {
+		t.Error("expected a failed candidate lookup to fail the import")
+	}
+	if len(repo.quotes) != 4 {
+		t.Errorf("expected nothing stored after the failed lookup, got %d quotes", len(repo.quotes))
+	}
+}
@@ -0,0 +1,134 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidQuote is returned when a quote or a request about quotes fails
+// validation.
+var ErrInvalidQuote = errors.New("invalid quote")
+
+// QuotePatch holds the fields of a partial update. Nil fields are left as
+// they are.
+type QuotePatch struct {
+	Text   *string   `json:"text,omitempty"`
+	Tags   *[]string `json:"tags,omitempty"`
+	Lang   *string   `json:"lang,omitempty"`
+	Author *string   `json:"author,omitempty"`
+
+	Work                *string `json:"work,omitempty"`
+	Year                *int    `json:"year,omitempty"`
+	License             *string `json:"license,omitempty"`
+	AttributionRequired *bool   `json:"attributionRequired,omitempty"`
+}
+
+// GetQuote returns a single quote, or repository.ErrNotFound.
+func (uc *QuoteUseCase) GetQuote(id int) (*entity.Quote, error) {
+	return uc.quoteRepo.FindByID(id)
+}
+
+// ReplaceQuote overwrites the editable fields of a quote. version is the
+// version the caller read; a stale one yields repository.ErrVersionConflict.
+func (uc *QuoteUseCase) ReplaceQuote(id, version int, replacement entity.Quote) (*entity.Quote, error) {
+	q, err := uc.loadForEdit(id, version)
+	if err != nil {
+		return nil, err
+	}
+	q.Text = replacement.Text
+	q.Tags = replacement.Tags
+	q.Lang = replacement.Lang
+	q.Author = replacement.Author
+	q.Work = replacement.Work
+	q.Year = replacement.Year
+	q.License = replacement.License
+	q.AttributionRequired = replacement.AttributionRequired
+	if q.Lang == "" {
+		q.Lang = defaultLang
+	}
+	return uc.saveEdit(q, version)
+}
+
+// PatchQuote updates the fields set in patch, with the same version check as
+// ReplaceQuote.
+func (uc *QuoteUseCase) PatchQuote(id, version int, patch QuotePatch) (*entity.Quote, error) {
+	q, err := uc.loadForEdit(id, version)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Text != nil {
+		q.Text = *patch.Text
+	}
+	if patch.Tags != nil {
+		q.Tags = *patch.Tags
+	}
+	if patch.Lang != nil {
+		q.Lang = *patch.Lang
+	}
+	if patch.Author != nil {
+		q.Author = *patch.Author
+	}
+	if patch.Work != nil {
+		q.Work = *patch.Work
+	}
+	if patch.Year != nil {
+		q.Year = *patch.Year
+	}
+	if patch.License != nil {
+		q.License = *patch.License
+	}
+	if patch.AttributionRequired != nil {
+		q.AttributionRequired = *patch.AttributionRequired
+	}
+	return uc.saveEdit(q, version)
+}
+
+// DeleteQuote soft-deletes a quote and republishes the quotes metadata. A
+// version of 0 deletes whatever version is stored.
+func (uc *QuoteUseCase) DeleteQuote(id, version int) error {
+	if err := uc.quoteRepo.Delete(id, version); err != nil {
+		return err
+	}
+	return uc.updateMetadata()
+}
+
+func (uc *QuoteUseCase) loadForEdit(id, version int) (*entity.Quote, error) {
+	q, err := uc.quoteRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if q.Version != version {
+		return nil, fmt.Errorf("%w: quote %d is at version %d", repository.ErrVersionConflict, id, q.Version)
+	}
+	return q, nil
+}
+
+// saveEdit validates an edited quote, refreshes its natural key and stores
+// it if nobody else changed it in the meantime.
+func (uc *QuoteUseCase) saveEdit(q *entity.Quote, version int) (*entity.Quote, error) {
+	if err := ValidateQuote(q); err != nil {
+		return nil, err
+	}
+
+	q.NaturalKey = naturalKey(q, q.ImportSource)
+	setFingerprint(q)
+	existing, err := uc.quoteRepo.FindByNaturalKeys([]string{q.NaturalKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up quote: %w", err)
+	}
+	for _, other := range existing {
+		if other.Id != q.Id {
+			return nil, fmt.Errorf("%w: quote %d", ErrDuplicateQuote, other.Id)
+		}
+	}
+
+	if err := uc.quoteRepo.UpdateVersion(q, version); err != nil {
+		return nil, err
+	}
+	if err := uc.updateMetadata(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
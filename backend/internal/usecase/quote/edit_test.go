@@ -0,0 +1,54 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"testing"
+)
+
+func TestPatchAndDeleteQuote(t *testing.T) {
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	uc := NewQuoteUseCase(repo, nil, nil, meta)
+	first, err := uc.CreateQuote(&entity.Quote{Text: "Be kind.", Tags: []string{"life"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.CreateQuote(&entity.Quote{Text: "Stay curious."}); err != nil {
+		t.Fatal(err)
+	}
+
+	text := "Be kind, always."
+	updated, err := uc.PatchQuote(first.Id, 1, QuotePatch{Text: &text})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Version != 2 || updated.Text != text || len(updated.Tags) != 1 {
+		t.Errorf("unexpected patched quote: %+v", updated)
+	}
+
+	if _, err := uc.PatchQuote(first.Id, 1, QuotePatch{Text: &text}); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("expected a version conflict, got %v", err)
+	}
+	dup := "stay  CURIOUS."
+	if _, err := uc.PatchQuote(first.Id, 2, QuotePatch{Text: &dup}); !errors.Is(err, ErrDuplicateQuote) {
+		t.Errorf("expected a duplicate error, got %v", err)
+	}
+	empty := " "
+	if _, err := uc.ReplaceQuote(first.Id, 2, entity.Quote{Text: empty}); !errors.Is(err, ErrInvalidQuote) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+
+	if err := uc.DeleteQuote(first.Id, 1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("expected a version conflict, got %v", err)
+	}
+	if err := uc.DeleteQuote(first.Id, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := uc.GetQuote(first.Id); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected the deleted quote to be gone, got %v", err)
+	}
+	if meta.quoteUpdates != 4 {
+		t.Errorf("expected 4 metadata publishes, got %d", meta.quoteUpdates)
+	}
+}
@@ -0,0 +1,53 @@
+package quote
+
+import (
+	"bufio"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// decodeText wraps r so it yields UTF-8. A UTF-8 or UTF-16 byte order mark
+// wins; without one, UTF-16 is recognised by the NUL bytes ASCII text leaves
+// in every other position, and anything else is read as UTF-8.
+func decodeText(r io.Reader) io.Reader {
+	br := bufio.NewReaderSize(r, 4096)
+	head, _ := br.Peek(512)
+
+	var fallback encoding.Encoding = unicode.UTF8
+	if order, ok := detectUTF16(head); ok {
+		fallback = unicode.UTF16(order, unicode.IgnoreBOM)
+	}
+
+	return transform.NewReader(br, unicode.BOMOverride(fallback.NewDecoder()))
+}
+
+// detectUTF16 guesses the byte order of BOM-less UTF-16 text. ok is false
+// when the sample does not look like UTF-16.
+func detectUTF16(sample []byte) (order unicode.Endianness, ok bool) {
+	if len(sample) < 4 {
+		return order, false
+	}
+	var evenZeros, oddZeros int
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+
+	half := len(sample) / 4
+	switch {
+	case oddZeros > half && evenZeros == 0:
+		return unicode.LittleEndian, true
+	case evenZeros > half && oddZeros == 0:
+		return unicode.BigEndian, true
+	}
+	return order, false
+}
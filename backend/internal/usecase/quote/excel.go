@@ -0,0 +1,57 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrInvalidSpreadsheet is returned when an uploaded workbook cannot be read
+// or does not contain the requested sheet.
+var ErrInvalidSpreadsheet = errors.New("invalid spreadsheet")
+
+// ImportFromExcel imports quotes from an .xlsx workbook. sheet selects the
+// tab to read; the first tab is used when it is empty. Columns are matched by
+// header name through the mapping.
+func (uc *QuoteUseCase) ImportFromExcel(r io.Reader, sheet string, mapping entity.ColumnMapping, opts ImportOptions) (*ImportResult, error) {
+	imp, err := uc.newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	workbook, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpreadsheet, err)
+	}
+	defer workbook.Close()
+
+	if sheet == "" {
+		sheet = workbook.GetSheetName(0)
+	}
+	if index, err := workbook.GetSheetIndex(sheet); err != nil || index < 0 {
+		return nil, fmt.Errorf("%w: sheet %q not found", ErrInvalidSpreadsheet, sheet)
+	}
+
+	cells, err := workbook.GetRows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpreadsheet, err)
+	}
+	if len(cells) == 0 {
+		return nil, fmt.Errorf("%w: sheet %q is empty", ErrInvalidSpreadsheet, sheet)
+	}
+
+	rows := make([][]interface{}, len(cells))
+	for i, row := range cells {
+		rows[i] = make([]interface{}, len(row))
+		for j, cell := range row {
+			rows[i][j] = cell
+		}
+	}
+
+	return imp.execute(func() error {
+		return processRows(sheet, 1, rows, mapping, "", imp.emit(sheet))
+	})
+}
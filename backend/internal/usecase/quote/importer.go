@@ -0,0 +1,506 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	defaultBatchSize = 100
+	// maxReportedErrors caps the per-row errors returned for very large
+	// imports; Failed still counts every rejected row.
+	maxReportedErrors = 1000
+)
+
+// Import policies accepted by ImportOptions.Policy.
+const (
+	// PolicySkipBadRows stores every valid row; each batch is written in its
+	// own transaction and a failing batch is retried row by row so only the
+	// offending rows are rejected.
+	PolicySkipBadRows = "skip-bad-rows"
+	// PolicyAllOrNothing runs the whole import in one transaction that is
+	// rolled back if any row is rejected.
+	PolicyAllOrNothing = "all-or-nothing"
+)
+
+// errRollback aborts an all-or-nothing import transaction.
+var errRollback = errors.New("import rolled back")
+
+// ImportOptions controls how an import is reconciled with the stored quotes.
+// Source names where the quotes come from and scopes their source IDs;
+// Sheets imports derive it from the spreadsheet when it is empty. Mirror
+// deletes the quotes of Source that the import no longer contains. Policy
+// defaults to QUOTE_IMPORT_POLICY, or PolicySkipBadRows. DryRun runs the
+// import without writing anything and reports the diff it would apply.
+type ImportOptions struct {
+	Source string `json:"source,omitempty"`
+	Mirror bool   `json:"mirror,omitempty"`
+	Policy string `json:"policy,omitempty"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// ImportResult summarises a bulk quote import.
+type ImportResult struct {
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Deleted   int `json:"deleted"`
+	Failed    int `json:"failed"`
+	// Errors lists the first maxReportedErrors rejected rows; Failed counts
+	// them all.
+	Errors        []RowError     `json:"errors,omitempty"`
+	SkippedSheets []SkippedSheet `json:"skippedSheets,omitempty"`
+	// MirrorSkipped explains why a mirror import deleted nothing.
+	MirrorSkipped string `json:"mirrorSkipped,omitempty"`
+	// RolledBack is set when an all-or-nothing import stored nothing
+	// because some rows were rejected.
+	RolledBack bool `json:"rolledBack,omitempty"`
+	// Diff is set for dry runs, whose counts are what the import would do.
+	Diff *ImportDiff `json:"diff,omitempty"`
+	// NearDuplicates flags rows that closely resemble another quote. They
+	// are imported all the same. NearDuplicateCount counts them all when the
+	// list is capped.
+	NearDuplicates     []NearDuplicate `json:"nearDuplicates,omitempty"`
+	NearDuplicateCount int             `json:"nearDuplicateCount,omitempty"`
+}
+
+// importRow is a parsed quote waiting to be reconciled.
+type importRow struct {
+	sheet string
+	row   int
+	quote entity.Quote
+}
+
+// importer upserts parsed quotes by natural key a batch at a time. Rows
+// repeating a key already seen in the same import are rejected.
+type importer struct {
+	uc *QuoteUseCase
+	// repo is the quote repository, or the import transaction for
+	// all-or-nothing imports.
+	repo    repository.QuoteRepository
+	opts    ImportOptions
+	run     string
+	size    int
+	seen    map[string]string
+	pending []importRow
+	result  *ImportResult
+	// incomplete is set when some source rows could not be matched to a
+	// key, so a mirror import must not delete anything.
+	incomplete bool
+	// translations is set when the source maps translation groups, as
+	// all-tabs imports do. Otherwise the stored groups are kept.
+	translations bool
+	// err aborts an import that could not read the stored quotes; no
+	// batch is written or planned after it is set.
+	err error
+	// dupThreshold is the similarity at which rows are flagged as near
+	// duplicates.
+	dupThreshold float64
+}
+
+func (uc *QuoteUseCase) newImporter(opts ImportOptions) (*importer, error) {
+	opts.Source = strings.TrimSpace(opts.Source)
+	if opts.Mirror && opts.Source == "" {
+		return nil, fmt.Errorf("%w: mirror mode requires a source", ErrInvalidImport)
+	}
+	if opts.Policy == "" {
+		opts.Policy = defaultPolicy()
+	}
+	if opts.Policy != PolicySkipBadRows && opts.Policy != PolicyAllOrNothing {
+		return nil, fmt.Errorf("%w: unknown policy %q", ErrInvalidImport, opts.Policy)
+	}
+	imp := &importer{
+		uc:           uc,
+		repo:         uc.quoteRepo,
+		opts:         opts,
+		run:          strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:         batchSize(),
+		seen:         make(map[string]string),
+		result:       &ImportResult{},
+		dupThreshold: duplicateThreshold(),
+	}
+	if opts.DryRun {
+		imp.result.Diff = &ImportDiff{}
+	}
+	return imp, nil
+}
+
+// emit returns the row callback handed to the parsers for one sheet.
+func (imp *importer) emit(sheet string) func(int, *entity.Quote, ...RowError) {
+	return func(row int, q *entity.Quote, rowErrs ...RowError) {
+		imp.result.Total++
+		if len(rowErrs) > 0 {
+			imp.reject(rowErrs...)
+			return
+		}
+		imp.add(importRow{sheet: sheet, row: row, quote: *q})
+	}
+}
+
+func (imp *importer) add(r importRow) {
+	q := &r.quote
+	q.Version = 1
+	q.Tags = normalizeTags(q.Tags)
+	q.NaturalKey = naturalKey(q, imp.opts.Source)
+	setFingerprint(q)
+	q.ImportSource = imp.opts.Source
+	q.ImportRun = imp.run
+
+	if first, ok := imp.seen[q.NaturalKey]; ok {
+		imp.result.Failed++
+		imp.report(RowError{Sheet: r.sheet, Row: r.row, Message: "duplicate of " + first})
+		return
+	}
+	imp.seen[q.NaturalKey] = rowLabel(r.sheet, r.row)
+
+	imp.pending = append(imp.pending, r)
+	if len(imp.pending) >= imp.size {
+		imp.flush()
+	}
+}
+
+// reject records a row that could not be imported, with one error per
+// problem found in it.
+func (imp *importer) reject(rowErrs ...RowError) {
+	imp.result.Failed++
+	imp.incomplete = true
+	for _, rowErr := range rowErrs {
+		imp.report(rowErr)
+	}
+}
+
+// skipSheet records a tab that was left out of the import.
+func (imp *importer) skipSheet(sheet, reason string) {
+	imp.result.SkippedSheets = append(imp.result.SkippedSheets, SkippedSheet{Sheet: sheet, Reason: reason})
+}
+
+// report lists a rejected row, up to maxReportedErrors of them.
+func (imp *importer) report(rowErr RowError) {
+	if len(imp.result.Errors) >= maxReportedErrors {
+		return
+	}
+	imp.result.Errors = append(imp.result.Errors, rowErr)
+	if imp.result.Diff != nil {
+		imp.result.Diff.Skip = append(imp.result.Diff.Skip, DiffEntry{Sheet: rowErr.Sheet, Row: rowErr.Row, Reason: rowErr.Message})
+	}
+}
+
+// batchOutcome counts what a committed batch did.
+type batchOutcome struct {
+	created, updated, unchanged int
+}
+
+// flush writes the pending quotes in one transaction. If that fails the
+// rows are retried one transaction each so the failure can be pinned on the
+// rows that caused it.
+func (imp *importer) flush() {
+	if len(imp.pending) == 0 {
+		return
+	}
+	batch := imp.pending
+	imp.pending = nil
+	if imp.err != nil {
+		return
+	}
+	if err := imp.flagNearDuplicates(batch); err != nil {
+		imp.err = err
+		return
+	}
+
+	if imp.opts.DryRun {
+		imp.planBatch(batch)
+		return
+	}
+
+	outcome, err := imp.writeBatch(batch)
+	if err == nil {
+		imp.count(outcome)
+		return
+	}
+	log.Printf("Failed to store batch starting at %s, retrying row by row: %v", rowLabel(batch[0].sheet, batch[0].row), err)
+
+	for _, r := range batch {
+		outcome, err := imp.writeBatch([]importRow{r})
+		if err != nil {
+			imp.reject(RowError{Sheet: r.sheet, Row: r.row, Message: fmt.Sprintf("failed to store quote: %v", err)})
+			continue
+		}
+		imp.count(outcome)
+	}
+}
+
+func (imp *importer) count(outcome batchOutcome) {
+	imp.result.Created += outcome.created
+	imp.result.Updated += outcome.updated
+	imp.result.Unchanged += outcome.unchanged
+}
+
+// writeBatch looks the rows up by natural key, inserts the new ones,
+// rewrites the changed ones and leaves the rest alone, all in one
+// transaction.
+func (imp *importer) writeBatch(batch []importRow) (batchOutcome, error) {
+	var outcome batchOutcome
+	err := imp.repo.Transaction(func(tx repository.QuoteRepository) error {
+		outcome = batchOutcome{}
+
+		keys := make([]string, len(batch))
+		for i, r := range batch {
+			keys[i] = r.quote.NaturalKey
+		}
+		existing, err := tx.FindByNaturalKeys(keys)
+		if err != nil {
+			return err
+		}
+		byKey := make(map[string]entity.Quote, len(existing))
+		for _, q := range existing {
+			byKey[q.NaturalKey] = q
+		}
+
+		var created []entity.Quote
+		var unchanged []string
+		for _, r := range batch {
+			old, ok := byKey[r.quote.NaturalKey]
+			if !ok {
+				created = append(created, r.quote)
+				continue
+			}
+			q := imp.incoming(old, r.quote)
+			switch {
+			case sameQuote(old, q):
+				unchanged = append(unchanged, r.quote.NaturalKey)
+			default:
+				q.Id = old.Id
+				if err := tx.UpdateVersion(&q, old.Version); err != nil {
+					return err
+				}
+				outcome.updated++
+			}
+		}
+
+		if err := tx.StoreBatch(created); err != nil {
+			return err
+		}
+		outcome.created = len(created)
+
+		if imp.opts.Mirror && len(unchanged) > 0 {
+			if err := tx.TouchImportRun(unchanged, imp.run); err != nil {
+				return err
+			}
+		}
+		outcome.unchanged = len(unchanged)
+		return nil
+	})
+	return outcome, err
+}
+
+// execute runs parse, which feeds rows through emit, and reconciles the
+// result according to the import policy. The quotes metadata is
+// republished once the quotes are committed.
+func (imp *importer) execute(parse func() error) (*ImportResult, error) {
+	if imp.opts.DryRun {
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		imp.flush()
+		if imp.err != nil {
+			return nil, imp.err
+		}
+		if imp.opts.Policy == PolicyAllOrNothing && imp.result.Failed > 0 {
+			imp.planRollback()
+			return imp.result, nil
+		}
+		imp.planMirror()
+		if imp.err != nil {
+			return nil, imp.err
+		}
+		return imp.result, nil
+	}
+
+	if imp.opts.Policy == PolicyAllOrNothing {
+		err := imp.uc.quoteRepo.Transaction(func(tx repository.QuoteRepository) error {
+			imp.repo = tx
+			if err := parse(); err != nil {
+				return err
+			}
+			imp.flush()
+			if imp.err != nil {
+				return imp.err
+			}
+			if imp.result.Failed > 0 {
+				return errRollback
+			}
+			return imp.mirror()
+		})
+		if errors.Is(err, errRollback) {
+			imp.result.RolledBack = true
+			imp.result.Created, imp.result.Updated, imp.result.Unchanged = 0, 0, 0
+			return imp.result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if err := parse(); err != nil {
+			return nil, err
+		}
+		imp.flush()
+		if imp.err != nil {
+			return imp.result, imp.err
+		}
+		if err := imp.mirror(); err != nil {
+			return imp.result, err
+		}
+	}
+
+	if err := imp.uc.updateMetadata(); err != nil {
+		return imp.result, fmt.Errorf("failed to update metadata: %w", err)
+	}
+	return imp.result, nil
+}
+
+// mirror removes the source's quotes that the import did not contain,
+// unless some rows or sheets could not be matched.
+func (imp *importer) mirror() error {
+	if !imp.opts.Mirror {
+		return nil
+	}
+	if imp.incomplete {
+		imp.result.MirrorSkipped = "some rows or sheets could not be imported, so no quotes were deleted"
+		return nil
+	}
+	deleted, err := imp.repo.DeleteStale(imp.opts.Source, imp.run)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale quotes: %w", err)
+	}
+	imp.result.Deleted = int(deleted)
+	return nil
+}
+
+// incoming returns the quote an import would store over stored: the row,
+// keeping the stored translation group unless the source maps groups.
+func (imp *importer) incoming(stored, row entity.Quote) entity.Quote {
+	if !imp.translations {
+		row.TranslationGroup = stored.TranslationGroup
+	}
+	return row
+}
+
+// naturalKey identifies a quote across imports: its source ID scoped to the
+// source, or else a hash of its normalised text, plus its language.
+func naturalKey(q *entity.Quote, source string) string {
+	if q.ExternalId != "" {
+		return fmt.Sprintf("id:%s:%s:%s", source, q.ExternalId, q.Lang)
+	}
+	sum := sha256.Sum256([]byte(normalizeText(q.Text)))
+	return fmt.Sprintf("text:%s:%s", hex.EncodeToString(sum[:]), q.Lang)
+}
+
+// normalizeText folds Unicode normalisation, case and whitespace so that
+// cosmetic edits do not create a new quote.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFC.String(text))), " ")
+}
+
+// sameQuote reports whether an import would leave the stored quote as is.
+func sameQuote(stored, incoming entity.Quote) bool {
+	return len(changedFields(stored, incoming)) == 0
+}
+
+// changedFields names the fields an import would rewrite.
+func changedFields(stored, incoming entity.Quote) []string {
+	var changed []string
+	if stored.Text != incoming.Text {
+		changed = append(changed, "text")
+	}
+	if stored.Lang != incoming.Lang {
+		changed = append(changed, "lang")
+	}
+	if !sameTags(stored.Tags, incoming.Tags) {
+		changed = append(changed, "tags")
+	}
+	if stored.Author != incoming.Author {
+		changed = append(changed, "author")
+	}
+	if stored.Work != incoming.Work {
+		changed = append(changed, "work")
+	}
+	if stored.Year != incoming.Year {
+		changed = append(changed, "year")
+	}
+	if stored.License != incoming.License {
+		changed = append(changed, "license")
+	}
+	if stored.AttributionRequired != incoming.AttributionRequired {
+		changed = append(changed, "attributionRequired")
+	}
+	if stored.ExternalId != incoming.ExternalId {
+		changed = append(changed, "externalId")
+	}
+	if stored.TranslationGroup != incoming.TranslationGroup {
+		changed = append(changed, "translationGroup")
+	}
+	if stored.ImportSource != incoming.ImportSource {
+		changed = append(changed, "source")
+	}
+	return changed
+}
+
+func sameTags(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// normalizeTags trims tags and drops empty and repeated ones, comparing
+// case-insensitively and keeping the first spelling.
+func normalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+func rowLabel(sheet string, row int) string {
+	if sheet == "" {
+		return fmt.Sprintf("row %d", row)
+	}
+	return fmt.Sprintf("%s!%d", sheet, row)
+}
+
+// defaultPolicy reads QUOTE_IMPORT_POLICY, falling back to PolicySkipBadRows.
+func defaultPolicy() string {
+	if policy := os.Getenv("QUOTE_IMPORT_POLICY"); policy != "" {
+		return policy
+	}
+	return PolicySkipBadRows
+}
+
+// batchSize reads BATCH_SIZE_QUOTE, falling back to defaultBatchSize.
+func batchSize() int {
+	if n, err := strconv.Atoi(os.Getenv("BATCH_SIZE_QUOTE")); err == nil && n > 0 {
+		return n
+	}
+	return defaultBatchSize
+}
@@ -0,0 +1,238 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultColumnMapping matches headers named after the quote fields.
+var DefaultColumnMapping = entity.ColumnMapping{
+	Text:   "text",
+	Tags:   "tags",
+	Lang:   "lang",
+	Author: "author",
+	Id:     "id",
+
+	Work:                "work",
+	Year:                "year",
+	License:             "license",
+	AttributionRequired: "attributionRequired",
+}
+
+// HeaderError reports mapped headers that are missing from the header row.
+// Unknown lists the headers present that no field is mapped to, which is
+// usually where the intended column is hiding.
+type HeaderError struct {
+	Sheet   string   `json:"sheet,omitempty"`
+	Missing []string `json:"missing"`
+	Unknown []string `json:"unknown"`
+}
+
+func (e *HeaderError) Error() string {
+	msg := fmt.Sprintf("missing headers: %s", strings.Join(e.Missing, ", "))
+	if e.Sheet != "" {
+		msg = e.Sheet + ": " + msg
+	}
+	if len(e.Unknown) > 0 {
+		msg += fmt.Sprintf(" (unmapped headers: %s)", strings.Join(e.Unknown, ", "))
+	}
+	return msg
+}
+
+// mergeMapping overlays the non-empty fields of override on base.
+func mergeMapping(base, override entity.ColumnMapping) entity.ColumnMapping {
+	if override.Text != "" {
+		base.Text = override.Text
+	}
+	if override.Tags != "" {
+		base.Tags = override.Tags
+	}
+	if override.Lang != "" {
+		base.Lang = override.Lang
+	}
+	if override.Author != "" {
+		base.Author = override.Author
+	}
+	if override.Id != "" {
+		base.Id = override.Id
+	}
+	if override.Work != "" {
+		base.Work = override.Work
+	}
+	if override.Year != "" {
+		base.Year = override.Year
+	}
+	if override.License != "" {
+		base.License = override.License
+	}
+	if override.AttributionRequired != "" {
+		base.AttributionRequired = override.AttributionRequired
+	}
+	return base
+}
+
+// columns holds the resolved position of each field, -1 when absent.
+type columns struct {
+	text, tags, lang, author, id int
+
+	work, year, license, attribution int
+}
+
+// baselineColumns is the positional layout of the original quotes sheet:
+// tags in column A and the text in column B, under a header row whose names
+// are not checked.
+var baselineColumns = columns{
+	text: 1, tags: 0, lang: -1, author: -1, id: -1,
+	work: -1, year: -1, license: -1, attribution: -1,
+}
+
+// resolveColumns locates the mapped fields in a header row. Text is always
+// required; other fields are required only when the mapping names them
+// explicitly, otherwise their default header is used if present. Without a
+// mapping, a header row lacking a text column falls back to baselineColumns.
+func resolveColumns(sheet string, header []string, mapping entity.ColumnMapping) (columns, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, seen := positions[key]; key != "" && !seen {
+			positions[key] = i
+		}
+	}
+
+	var missing []string
+	used := make(map[int]bool)
+	locate := func(configured, fallback string, required bool) int {
+		name := configured
+		if name == "" {
+			name = fallback
+		}
+		if idx, ok := positions[strings.ToLower(strings.TrimSpace(name))]; ok {
+			used[idx] = true
+			return idx
+		}
+		if required || configured != "" {
+			missing = append(missing, name)
+		}
+		return -1
+	}
+
+	cols := columns{
+		text:   locate(mapping.Text, DefaultColumnMapping.Text, true),
+		tags:   locate(mapping.Tags, DefaultColumnMapping.Tags, false),
+		lang:   locate(mapping.Lang, DefaultColumnMapping.Lang, false),
+		author: locate(mapping.Author, DefaultColumnMapping.Author, false),
+		id:     locate(mapping.Id, DefaultColumnMapping.Id, false),
+
+		work:        locate(mapping.Work, DefaultColumnMapping.Work, false),
+		year:        locate(mapping.Year, DefaultColumnMapping.Year, false),
+		license:     locate(mapping.License, DefaultColumnMapping.License, false),
+		attribution: locate(mapping.AttributionRequired, DefaultColumnMapping.AttributionRequired, false),
+	}
+	if len(missing) == 0 {
+		return cols, nil
+	}
+	if cols.text < 0 && mapping == (entity.ColumnMapping{}) {
+		return baselineColumns, nil
+	}
+
+	unknown := []string{}
+	for i, name := range header {
+		if !used[i] && strings.TrimSpace(name) != "" {
+			unknown = append(unknown, strings.TrimSpace(name))
+		}
+	}
+	return cols, &HeaderError{Sheet: sheet, Missing: missing, Unknown: unknown}
+}
+
+// toQuote builds a quote from the cells of one row and validates it. cell
+// returns the trimmed value at a column index, or "" when the index is -1 or
+// out of range. lang is used when the row has no language of its own. A
+// rejected row returns one error per failing field, each pointing at the
+// field's column.
+func (c columns) toQuote(sheet string, row int, cell func(int) string, lang string) (*entity.Quote, []RowError) {
+	text := cell(c.text)
+	if text == "" {
+		return nil, []RowError{{
+			Sheet:   sheet,
+			Row:     row,
+			Column:  columnName(c.text),
+			Message: "quote text is empty",
+		}}
+	}
+
+	if rowLang := cell(c.lang); rowLang != "" {
+		lang = rowLang
+	}
+
+	year, err := parseYear(cell(c.year))
+	if err != nil {
+		return nil, []RowError{{Sheet: sheet, Row: row, Column: columnName(c.year), Message: err.Error()}}
+	}
+	attribution, err := parseFlag(cell(c.attribution))
+	if err != nil {
+		return nil, []RowError{{Sheet: sheet, Row: row, Column: columnName(c.attribution), Message: err.Error()}}
+	}
+
+	q := &entity.Quote{
+		Text:                text,
+		Tags:                processTags(cell(c.tags)),
+		Lang:                langOrDefault(lang),
+		Author:              cell(c.author),
+		ExternalId:          cell(c.id),
+		Work:                cell(c.work),
+		Year:                year,
+		License:             cell(c.license),
+		AttributionRequired: attribution,
+	}
+	if errs := validateRow(sheet, row, q, c.column); errs != nil {
+		return nil, errs
+	}
+	return q, nil
+}
+
+// column returns the letter of the column a quote field was read from, or
+// "" when the field has no column.
+func (c columns) column(field string) string {
+	idx := map[string]int{
+		"text":                c.text,
+		"tags":                c.tags,
+		"lang":                c.lang,
+		"author":              c.author,
+		"work":                c.work,
+		"year":                c.year,
+		"license":             c.license,
+		"attributionRequired": c.attribution,
+	}
+	if i, ok := idx[field]; ok && i >= 0 {
+		return columnName(i)
+	}
+	return ""
+}
+
+// parseYear reads a year cell. Years before the common era are negative;
+// empty means unknown.
+func parseYear(raw string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	year, err := strconv.Atoi(raw)
+	if err != nil || year == 0 || year > time.Now().Year() {
+		return 0, fmt.Errorf("invalid year %q", raw)
+	}
+	return year, nil
+}
+
+// parseFlag reads a yes/no cell such as "yes", "true", "x" or "0"; empty
+// means no.
+func parseFlag(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "", "no", "n", "false", "0":
+		return false, nil
+	case "yes", "y", "true", "1", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid yes/no value %q", raw)
+}
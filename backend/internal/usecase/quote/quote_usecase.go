@@ -0,0 +1,226 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"backend/internal/domain/service"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultLang      = "en-US"
+	defaultReadRange = "English"
+
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ErrInvalidSheetLink is returned when no spreadsheet ID can be read from the
+// supplied Google Sheets link.
+var ErrInvalidSheetLink = errors.New("invalid Google Sheets link")
+
+// ErrDuplicateQuote is returned when a quote with the same natural key, that
+// is the same text and language, already exists.
+var ErrDuplicateQuote = errors.New("quote already exists")
+
+type QuoteUseCase struct {
+	quoteRepo       repository.QuoteRepository
+	profileRepo     repository.ImportProfileRepository
+	sheetsService   service.SheetsService
+	metadataService service.MetadataService
+}
+
+func NewQuoteUseCase(repo repository.QuoteRepository, profiles repository.ImportProfileRepository, sheets service.SheetsService, meta service.MetadataService) *QuoteUseCase {
+	return &QuoteUseCase{
+		quoteRepo:       repo,
+		profileRepo:     profiles,
+		sheetsService:   sheets,
+		metadataService: meta,
+	}
+}
+
+// SheetImportRequest describes a Google Sheets import. Range and Mapping
+// override the values stored in the named Profile.
+//
+// With AllTabs set every tab is imported as its own language: tab names are
+// mapped to BCP 47 codes through LangMap, SHEET_LANG_MAP and the built-in
+// table, and rows sharing an ID across tabs become translations of each
+// other. Range is ignored in that mode.
+//
+// The import source defaults to the spreadsheet and range, or just the
+// spreadsheet for AllTabs.
+type SheetImportRequest struct {
+	ImportOptions
+	GoogleSheetsLink string               `json:"googleSheetsLink"`
+	Range            string               `json:"range,omitempty"`
+	Mapping          entity.ColumnMapping `json:"mapping,omitempty"`
+	Profile          string               `json:"profile,omitempty"`
+	AllTabs          bool                 `json:"allTabs,omitempty"`
+	LangMap          map[string]string    `json:"langMap,omitempty"`
+}
+
+// CreateQuote validates and stores a single quote and republishes the quotes
+// metadata. Any client supplied ID is ignored.
+func (uc *QuoteUseCase) CreateQuote(quote *entity.Quote) (*entity.Quote, error) {
+	quote.Id, quote.Version = 0, 1
+	if strings.TrimSpace(quote.Lang) == "" {
+		quote.Lang = defaultLang
+	}
+	if err := ValidateQuote(quote); err != nil {
+		return nil, err
+	}
+	quote.NaturalKey = naturalKey(quote, "")
+	setFingerprint(quote)
+	quote.ImportSource, quote.ImportRun = "", ""
+
+	existing, err := uc.quoteRepo.FindByNaturalKeys([]string{quote.NaturalKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up quote: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%w: quote %d", ErrDuplicateQuote, existing[0].Id)
+	}
+
+	id, err := uc.quoteRepo.Store(quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert quote: %w", err)
+	}
+	quote.Id = id
+
+	if err := uc.updateMetadata(); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// ListQuotes returns one page of quotes matching the filter and the cursor
+// for the next page.
+func (uc *QuoteUseCase) ListQuotes(filter repository.QuoteFilter) ([]entity.Quote, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	filter.Author = strings.TrimSpace(filter.Author)
+	return uc.quoteRepo.List(filter)
+}
+
+// ImportFromSheet reads the quotes from a Google Sheets link, upserts them and
+// republishes the quotes metadata.
+func (uc *QuoteUseCase) ImportFromSheet(req SheetImportRequest) (*ImportResult, error) {
+	return uc.importSheet(uc.sheetsService, req)
+}
+
+// importSheet runs a Sheets import reading through sheets, which is either
+// the live service or a snapshot taken by a sync.
+func (uc *QuoteUseCase) importSheet(sheets service.SheetsService, req SheetImportRequest) (*ImportResult, error) {
+	spreadsheetID, err := extractSpreadsheetID(req.GoogleSheetsLink)
+	if err != nil {
+		return nil, err
+	}
+
+	readRange, mapping, err := uc.resolveProfile(req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := req.ImportOptions
+	if opts.Source == "" {
+		opts.Source = "sheets:" + spreadsheetID
+		if !req.AllTabs {
+			opts.Source += ":" + readRange
+		}
+	}
+	imp, err := uc.newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AllTabs {
+		return uc.importAllTabs(sheets, imp, spreadsheetID, mapping, req.LangMap)
+	}
+
+	rows, err := sheets.ReadRange(spreadsheetID, readRange)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := sheetName(readRange)
+	return imp.execute(func() error {
+		return processRows(sheet, rangeStartRow(readRange), rows, mapping, "", imp.emit(sheet))
+	})
+}
+
+// resolveProfile layers the request's range and mapping over the named
+// profile, if any, and the defaults.
+func (uc *QuoteUseCase) resolveProfile(req SheetImportRequest) (string, entity.ColumnMapping, error) {
+	readRange := defaultReadRange
+	var mapping entity.ColumnMapping
+
+	if req.Profile != "" {
+		profile, err := uc.profileRepo.FindByName(req.Profile)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return "", mapping, fmt.Errorf("%w: unknown import profile %q", ErrInvalidImport, req.Profile)
+			}
+			return "", mapping, err
+		}
+		if profile.Range != "" {
+			readRange = profile.Range
+		}
+		mapping = profile.Mapping
+	}
+
+	if req.Range != "" {
+		readRange = req.Range
+	}
+	return readRange, mergeMapping(mapping, req.Mapping), nil
+}
+
+// SaveProfile creates or replaces a named import profile.
+func (uc *QuoteUseCase) SaveProfile(profile *entity.ImportProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return fmt.Errorf("%w: profile name is required", ErrInvalidImport)
+	}
+	return uc.profileRepo.Save(profile)
+}
+
+// ListProfiles returns every saved import profile.
+func (uc *QuoteUseCase) ListProfiles() ([]entity.ImportProfile, error) {
+	return uc.profileRepo.FindAll()
+}
+
+// PublishMetadata republishes quotesMetadata.json after quotes were changed
+// outside this use case.
+func (uc *QuoteUseCase) PublishMetadata() error {
+	return uc.updateMetadata()
+}
+
+func (uc *QuoteUseCase) updateMetadata() error {
+	quotes, err := uc.quoteRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	attachTranslations(quotes)
+	return uc.metadataService.UpdateQuoteMetadata(quotes)
+}
+
+// extractSpreadsheetID pulls the sheet ID out of a Google Sheets link.
+func extractSpreadsheetID(sheetLink string) (string, error) {
+	parsedURL, err := url.Parse(sheetLink)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSheetLink, err)
+	}
+	parts := strings.Split(parsedURL.Path, "/")
+	for i, part := range parts {
+		if part == "d" && i+1 < len(parts) {
+			return parts[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("%w: spreadsheet ID not found in URL", ErrInvalidSheetLink)
+}
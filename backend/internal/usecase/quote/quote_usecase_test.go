@@ -0,0 +1,120 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExtractSpreadsheetID(t *testing.T) {
+	id, err := extractSpreadsheetID("https://docs.google.com/spreadsheets/d/abc_123-XYZ/edit#gid=0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != "abc_123-XYZ" {
+		t.Errorf("expected abc_123-XYZ, got %s", id)
+	}
+
+	if _, err := extractSpreadsheetID("https://docs.google.com/document/x"); !errors.Is(err, ErrInvalidSheetLink) {
+		t.Errorf("expected ErrInvalidSheetLink, got %v", err)
+	}
+}
+
+func TestProcessRows(t *testing.T) {
+	rows := [][]interface{}{
+		{"Tags", "Quote", "Author"},
+		{"life, hope", "Keep going.", "Anon"},
+		{"short row"},
+		{},
+		{"", "No tags here."},
+	}
+
+	var quotes []entity.Quote
+	var rowErrors []RowError
+	err := processRows("English", 4, rows, entity.ColumnMapping{Text: "quote"}, "", func(_ int, q *entity.Quote, rowErrs ...RowError) {
+		if len(rowErrs) > 0 {
+			rowErrors = append(rowErrors, rowErrs...)
+			return
+		}
+		quotes = append(quotes, *q)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(quotes) != 2 {
+		t.Fatalf("expected 2 quotes, got %d", len(quotes))
+	}
+	want := []RowError{{Sheet: "English", Row: 6, Column: "B", Message: "quote text is empty"}}
+	if !reflect.DeepEqual(rowErrors, want) {
+		t.Errorf("expected row errors %+v, got %+v", want, rowErrors)
+	}
+	if quotes[0].Text != "Keep going." || quotes[0].Author != "Anon" || !reflect.DeepEqual(quotes[0].Tags, []string{"life", "hope"}) {
+		t.Errorf("unexpected first quote: %+v", quotes[0])
+	}
+	if len(quotes[1].Tags) != 0 || quotes[1].Lang != defaultLang {
+		t.Errorf("unexpected second quote: %+v", quotes[1])
+	}
+}
+
+func TestProcessRowsMissingHeaders(t *testing.T) {
+	rows := [][]interface{}{
+		{"Tags", "Quote"},
+		{"life", "Keep going."},
+	}
+
+	err := processRows("English", 1, rows, entity.ColumnMapping{Author: "Writer"}, "", func(int, *entity.Quote, ...RowError) {
+		t.Error("expected no rows before the header is resolved")
+	})
+	var headerErr *HeaderError
+	if !errors.As(err, &headerErr) {
+		t.Fatalf("expected HeaderError, got %v", err)
+	}
+	if !reflect.DeepEqual(headerErr.Missing, []string{"text", "Writer"}) {
+		t.Errorf("unexpected missing headers: %v", headerErr.Missing)
+	}
+	if !reflect.DeepEqual(headerErr.Unknown, []string{"Quote"}) {
+		t.Errorf("unexpected unknown headers: %v", headerErr.Unknown)
+	}
+}
+
+func TestProcessRowsBaselineLayout(t *testing.T) {
+	rows := [][]interface{}{
+		{"Tags", "Quotes"},
+		{"life, hope", "Keep going."},
+	}
+
+	var quotes []entity.Quote
+	err := processRows("English", 1, rows, entity.ColumnMapping{}, "", func(_ int, q *entity.Quote, rowErrs ...RowError) {
+		if len(rowErrs) > 0 {
+			t.Errorf("unexpected row errors: %v", rowErrs)
+			return
+		}
+		quotes = append(quotes, *q)
+	})
+	if err != nil {
+		t.Fatalf("expected the positional layout to be used, got %v", err)
+	}
+	if len(quotes) != 1 || quotes[0].Text != "Keep going." || !reflect.DeepEqual(quotes[0].Tags, []string{"life", "hope"}) {
+		t.Errorf("unexpected quotes: %+v", quotes)
+	}
+}
+
+func TestRangeStartRow(t *testing.T) {
+	for readRange, want := range map[string]int{"English": 1, "English!A5:D": 5, "'Hindi tab'!B12": 12} {
+		if got := rangeStartRow(readRange); got != want {
+			t.Errorf("rangeStartRow(%q) = %d, want %d", readRange, got, want)
+		}
+	}
+	if got := sheetName("'Hindi tab'!B12"); got != "Hindi tab" {
+		t.Errorf("sheetName() = %q", got)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RowError reports why a spreadsheet row was rejected. Row is 1-based and
+// Column uses spreadsheet letters so editors can jump straight to the cell.
+type RowError struct {
+	Sheet   string `json:"sheet,omitempty"`
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	if e.Sheet == "" {
+		return fmt.Sprintf("row %d %s: %s", e.Row, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s!%s%d: %s", e.Sheet, e.Column, e.Row, e.Message)
+}
+
+// processRows maps sheet rows to quotes and hands each one, or its error,
+// to emit. The first row is the header and is resolved against the mapping;
+// startRow is the sheet row number of that header so errors point at the
+// right cell. Rows without a language column get lang, or the default. Blank
+// rows are skipped.
+func processRows(sheet string, startRow int, rows [][]interface{}, mapping entity.ColumnMapping, lang string, emit func(int, *entity.Quote, ...RowError)) error {
+	if len(rows) == 0 {
+		return fmt.Errorf("%w: no data found in sheet", ErrInvalidImport)
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, cell := range rows[0] {
+		header[i] = fmt.Sprintf("%v", cell)
+	}
+	cols, err := resolveColumns(sheet, header, mapping)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		cell := func(idx int) string {
+			if idx < 0 || idx >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(fmt.Sprintf("%v", row[idx]))
+		}
+
+		q, rowErrs := cols.toQuote(sheet, startRow+i+1, cell, lang)
+		emit(startRow+i+1, q, rowErrs...)
+	}
+	return nil
+}
+
+// rangeStart matches the first cell of an A1 range such as "English!B5:D".
+var rangeStart = regexp.MustCompile(`![A-Za-z]*(\d+)`)
+
+// rangeStartRow returns the sheet row a range begins on, 1 for whole tabs.
+func rangeStartRow(readRange string) int {
+	if m := rangeStart.FindStringSubmatch(readRange); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 1
+}
+
+// sheetName returns the tab part of an A1 range.
+func sheetName(readRange string) string {
+	if i := strings.LastIndex(readRange, "!"); i >= 0 {
+		return strings.Trim(readRange[:i], "'")
+	}
+	return readRange
+}
+
+func isBlankRow(row []interface{}) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(fmt.Sprintf("%v", cell)) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// processTags splits a comma separated tag cell.
+func processTags(rawTags string) []string {
+	cleaned := strings.ReplaceAll(rawTags, " ", "")
+	if cleaned == "" {
+		return []string{}
+	}
+	return strings.Split(cleaned, ",")
+}
+
+// columnName converts a 0-based column index to its spreadsheet letters
+// (0 -> A, 26 -> AA).
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
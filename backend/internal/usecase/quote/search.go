@@ -0,0 +1,62 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"fmt"
+	"strings"
+)
+
+// maxSearchLength bounds the query text handed to Postgres.
+const maxSearchLength = 256
+
+// SearchHit is a quote matching a search with its rank and a snippet of its
+// text, matches wrapped in <mark> tags.
+type SearchHit struct {
+	entity.Quote
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// SearchResult is one page of search hits. Total counts every match.
+type SearchResult struct {
+	Hits       []SearchHit `json:"results"`
+	Count      int         `json:"count"`
+	Total      int64       `json:"total"`
+	NextCursor string      `json:"nextCursor"`
+}
+
+// SearchQuotes runs a full-text search over quote text, author and work.
+// The query uses web search syntax: quoted phrases, "or" and "-" to
+// exclude.
+func (uc *QuoteUseCase) SearchQuotes(search repository.QuoteSearch) (*SearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	search.Lang = strings.TrimSpace(search.Lang)
+	if search.Query == "" {
+		return nil, fmt.Errorf("%w: search query is required", ErrInvalidQuote)
+	}
+	if len(search.Query) > maxSearchLength {
+		return nil, fmt.Errorf("%w: search query is longer than %d bytes", ErrInvalidQuote, maxSearchLength)
+	}
+	if search.Limit <= 0 {
+		search.Limit = defaultPageSize
+	}
+	if search.Limit > maxPageSize {
+		search.Limit = maxPageSize
+	}
+
+	hits, next, err := uc.quoteRepo.Search(search)
+	if err != nil {
+		return nil, err
+	}
+	total, err := uc.quoteRepo.CountSearch(search)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SearchResult{Hits: make([]SearchHit, len(hits)), Count: len(hits), Total: total, NextCursor: next}
+	for i, hit := range hits {
+		result.Hits[i] = SearchHit{Quote: hit.Quote, Rank: hit.Rank, Snippet: hit.Snippet}
+	}
+	return result, nil
+}
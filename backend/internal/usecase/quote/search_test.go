@@ -0,0 +1,51 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSearchQuotes(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	for _, q := range []entity.Quote{
+		{Text: "Stay hungry, stay foolish.", Tags: []string{"life"}},
+		{Text: "Stay curious.", Tags: []string{"work"}},
+		{Text: "Restez curieux.", Lang: "fr-FR"},
+		{Text: "Be kind."},
+	} {
+		if _, err := uc.CreateQuote(&q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := uc.SearchQuotes(repository.QuoteSearch{Query: " stay hungry ", Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Count != 1 || result.Total != 2 || result.Hits[0].Id != 1 || result.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", result)
+	}
+	result, err = uc.SearchQuotes(repository.QuoteSearch{Query: "stay hungry", Limit: 1, Cursor: result.NextCursor})
+	if err != nil || result.Count != 1 || result.Hits[0].Id != 2 || result.NextCursor != "" {
+		t.Fatalf("unexpected second page: %+v, %v", result, err)
+	}
+
+	result, err = uc.SearchQuotes(repository.QuoteSearch{Query: "stay", Tag: "work"})
+	if err != nil || result.Total != 1 || result.Hits[0].Id != 2 {
+		t.Errorf("expected the tag filter to narrow the results, got %+v, %v", result, err)
+	}
+	result, err = uc.SearchQuotes(repository.QuoteSearch{Query: "cur", Lang: "fr-FR"})
+	if err != nil || result.Total != 1 || result.Hits[0].Id != 3 {
+		t.Errorf("expected the lang filter to narrow the results, got %+v, %v", result, err)
+	}
+
+	for _, query := range []string{"  ", strings.Repeat("a", maxSearchLength+1)} {
+		if _, err := uc.SearchQuotes(repository.QuoteSearch{Query: query}); !errors.Is(err, ErrInvalidQuote) {
+			t.Errorf("expected query of length %d to be rejected, got %v", len(query), err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package quote
+
+import (
+	"backend/internal/infrastructure/googlesheets"
+	"backend/internal/infrastructure/googlesheets/sheetstest"
+	"testing"
+)
+
+func TestImportFromSheetOverHTTP(t *testing.T) {
+	srv := sheetstest.NewServer()
+	defer srv.Close()
+	srv.SetTab("doc1", "English", [][]interface{}{
+		{"ID", "Quote", "Tags"},
+		{"1", "Be kind.", "life, hope"},
+		{"2", "", "empty"},
+		{"3", "Stay curious.", ""},
+	})
+
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	sheets := googlesheets.NewSheetsServiceWithOptions(srv.ClientOptions()...)
+	uc := NewQuoteUseCase(repo, nil, sheets, meta)
+
+	req := SheetImportRequest{GoogleSheetsLink: "https://docs.google.com/spreadsheets/d/doc1/edit"}
+	req.Mapping.Text, req.Mapping.Id = "Quote", "ID"
+	result, err := uc.ImportFromSheet(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 2 || result.Failed != 1 || result.Errors[0].Row != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if repo.quotes[0].ExternalId != "1" || len(repo.quotes[0].Tags) != 2 || meta.quoteUpdates != 1 {
+		t.Errorf("unexpected stored quote: %+v", repo.quotes[0])
+	}
+
+	// a second import of the same sheet changes nothing
+	result, err = uc.ImportFromSheet(req)
+	if err != nil || result.Unchanged != 2 || result.Created != 0 {
+		t.Errorf("unexpected re-import: %+v, %v", result, err)
+	}
+}
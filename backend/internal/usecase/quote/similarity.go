@@ -0,0 +1,238 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"encoding/binary"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// fingerprintHashes is the MinHash signature length. Similarity is
+	// estimated in steps of 1/fingerprintHashes.
+	fingerprintHashes = 64
+	// fingerprintBands splits signatures for locality-sensitive hashing:
+	// quotes sharing any band are compared. Two-row bands find pairs down
+	// to a similarity of about 0.3.
+	fingerprintBands = 32
+	// shingleSize is the length in characters of the overlapping pieces
+	// that are hashed.
+	shingleSize = 4
+
+	defaultDuplicateThreshold = 0.8
+)
+
+// hashSeeds derives the MinHash functions from one fixed seed, so
+// fingerprints stay comparable across runs.
+var hashSeeds = func() [fingerprintHashes]uint64 {
+	var seeds [fingerprintHashes]uint64
+	x := uint64(0x5eed)
+	for i := range seeds {
+		x += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(x)
+	}
+	return seeds
+}()
+
+// trailingAttribution matches "— Author" style credits at the end of a
+// quote.
+var trailingAttribution = regexp.MustCompile(`^(.+?)\s+[-–—―~]+\s*\p{Lu}[\p{L}\p{M}.,'’ ]{0,59}$`)
+
+// foldText reduces a quote to what near-duplicate detection compares: NFC,
+// lower case, no trailing attribution, punctuation (straight or curly)
+// folded to spaces and whitespace collapsed.
+func foldText(text string) string {
+	text = strings.TrimSpace(norm.NFC.String(text))
+	if m := trailingAttribution.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// fingerprint computes the MinHash signature of a quote's folded text over
+// its character shingles.
+func fingerprint(text string) []uint32 {
+	folded := []rune(foldText(text))
+	if len(folded) == 0 {
+		return nil
+	}
+
+	var shingles []uint64
+	for i := 0; i == 0 || i+shingleSize <= len(folded); i++ {
+		end := i + shingleSize
+		if end > len(folded) {
+			end = len(folded)
+		}
+		h := fnv.New64a()
+		h.Write([]byte(string(folded[i:end])))
+		shingles = append(shingles, h.Sum64())
+	}
+
+	sig := make([]uint32, fingerprintHashes)
+	for i, seed := range hashSeeds {
+		min := ^uint32(0)
+		for _, s := range shingles {
+			if v := uint32(mix64(s ^ seed)); v < min {
+				min = v
+			}
+		}
+		sig[i] = min
+	}
+	return sig
+}
+
+// setFingerprint computes the fingerprint of q and its band hashes.
+func setFingerprint(q *entity.Quote) {
+	q.Fingerprint = fingerprint(q.Text)
+	q.Bands = bandHashes(q.Fingerprint)
+}
+
+// signature returns the stored fingerprint of q, computing it for quotes
+// stored before fingerprints were.
+func signature(q *entity.Quote) []uint32 {
+	if len(q.Fingerprint) == fingerprintHashes {
+		return q.Fingerprint
+	}
+	return fingerprint(q.Text)
+}
+
+// similarity estimates the Jaccard similarity of the shingle sets behind two
+// signatures.
+func similarity(a, b []uint32) float64 {
+	if len(a) != fingerprintHashes || len(b) != fingerprintHashes {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / fingerprintHashes
+}
+
+// mix64 is the SplitMix64 finaliser.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// dupEntry is a quote known to a dupIndex: a stored quote (id set) or a row
+// of the running import (label only).
+type dupEntry struct {
+	id    int
+	label string
+	key   string
+	lang  string
+	sig   []uint32
+}
+
+// dupIndex finds near duplicates among quotes of the same language.
+type dupIndex struct {
+	threshold float64
+	entries   []dupEntry
+	bands     map[int64][]int
+}
+
+func newDupIndex(threshold float64) *dupIndex {
+	return &dupIndex{threshold: threshold, bands: make(map[int64][]int)}
+}
+
+func (x *dupIndex) insert(e dupEntry) {
+	if len(e.sig) != fingerprintHashes {
+		return
+	}
+	for _, k := range bandHashes(e.sig) {
+		x.bands[k] = append(x.bands[k], len(x.entries))
+	}
+	x.entries = append(x.entries, e)
+}
+
+// match returns the most similar entry at or above the threshold that is in
+// the same language and is not the quote with natural key key itself.
+func (x *dupIndex) match(sig []uint32, lang, key string) (dupEntry, float64, bool) {
+	best, bestSim := -1, 0.0
+	for _, i := range x.candidates(sig) {
+		e := x.entries[i]
+		if e.lang != lang || (key != "" && e.key == key) {
+			continue
+		}
+		if sim := similarity(sig, e.sig); sim >= x.threshold && sim > bestSim {
+			best, bestSim = i, sim
+		}
+	}
+	if best < 0 {
+		return dupEntry{}, 0, false
+	}
+	return x.entries[best], bestSim, true
+}
+
+// candidates lists, in insertion order, the entries sharing a band with sig.
+func (x *dupIndex) candidates(sig []uint32) []int {
+	if len(sig) != fingerprintHashes {
+		return nil
+	}
+	seen := make(map[int]bool)
+	var out []int
+	for _, k := range bandHashes(sig) {
+		for _, i := range x.bands[k] {
+			if !seen[i] {
+				seen[i] = true
+				out = append(out, i)
+			}
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// bandHashes splits a signature into its LSH bands and hashes each band
+// together with its position. Quotes sharing any band hash are compared. A
+// quote without a signature has none, but a non-nil empty list, so it is
+// not taken for one stored before bands were.
+func bandHashes(sig []uint32) []int64 {
+	if len(sig) != fingerprintHashes {
+		return []int64{}
+	}
+	rows := fingerprintHashes / fingerprintBands
+	hashes := make([]int64, fingerprintBands)
+	buf := make([]byte, 1+4*rows)
+	for b := range hashes {
+		buf[0] = byte(b)
+		for r := 0; r < rows; r++ {
+			binary.LittleEndian.PutUint32(buf[1+4*r:], sig[b*rows+r])
+		}
+		h := fnv.New64a()
+		h.Write(buf)
+		hashes[b] = int64(h.Sum64())
+	}
+	return hashes
+}
+
+// duplicateThreshold reads QUOTE_DUPLICATE_THRESHOLD, falling back to
+// defaultDuplicateThreshold.
+func duplicateThreshold() float64 {
+	if t, err := strconv.ParseFloat(os.Getenv("QUOTE_DUPLICATE_THRESHOLD"), 64); err == nil && t > 0 && t <= 1 {
+		return t
+	}
+	return defaultDuplicateThreshold
+}
@@ -0,0 +1,250 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats accepted by ImportStream.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+const maxNDJSONLine = 1 << 20
+
+// ErrInvalidImport is returned when a CSV or NDJSON body cannot be parsed at
+// all, as opposed to individual rows failing validation.
+var ErrInvalidImport = errors.New("invalid import")
+
+// StreamOptions configures ImportStream.
+type StreamOptions struct {
+	ImportOptions
+	Format    string
+	Delimiter rune
+	Mapping   entity.ColumnMapping
+}
+
+// ImportStream imports quotes from a CSV or NDJSON body without loading it
+// into memory. Rows are decoded one at a time and upserted in batches of
+// BATCH_SIZE_QUOTE, each in its own transaction unless the import is
+// all-or-nothing.
+func (uc *QuoteUseCase) ImportStream(r io.Reader, opts StreamOptions) (*ImportResult, error) {
+	imp, err := uc.newImporter(opts.ImportOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case FormatCSV:
+		return imp.execute(func() error {
+			return readCSV(decodeText(r), opts, imp.emit(""))
+		})
+	case FormatNDJSON:
+		return imp.execute(func() error {
+			return readNDJSON(decodeText(r), opts.Mapping, imp.emit(""))
+		})
+	}
+	return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidImport, opts.Format)
+}
+
+// readCSV walks the records of a CSV body. The first record is the header
+// and is resolved against the column mapping.
+func readCSV(r io.Reader, opts StreamOptions, emit func(int, *entity.Quote, ...RowError)) error {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: unable to read header: %v", ErrInvalidImport, err)
+	}
+	cols, err := resolveColumns("", header, opts.Mapping)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				emit(parseErr.StartLine, nil, RowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+				continue
+			}
+			return fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if isBlankRecord(record) {
+			continue
+		}
+		cell := func(idx int) string {
+			if idx < 0 || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+		q, rowErrs := cols.toQuote("", line, cell, "")
+		emit(line, q, rowErrs...)
+	}
+}
+
+// readNDJSON walks a newline delimited JSON body, one quote object per line.
+// Keys are resolved through the mapping; tags may be an array or a comma
+// separated string.
+func readNDJSON(r io.Reader, mapping entity.ColumnMapping, emit func(int, *entity.Quote, ...RowError)) error {
+	mapping = mergeMapping(DefaultColumnMapping, mapping)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLine)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+			emit(line, nil, RowError{Row: line, Message: fmt.Sprintf("invalid JSON: %v", err)})
+			continue
+		}
+		fields := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			fields[strings.ToLower(k)] = v
+		}
+		str := func(key string) string {
+			switch v := fields[strings.ToLower(key)].(type) {
+			case string:
+				return strings.TrimSpace(v)
+			case float64:
+				return strconv.FormatFloat(v, 'f', -1, 64)
+			}
+			return ""
+		}
+
+		text := str(mapping.Text)
+		if text == "" {
+			emit(line, nil, RowError{Row: line, Column: mapping.Text, Message: "quote text is empty"})
+			continue
+		}
+
+		year, err := parseYear(str(mapping.Year))
+		if err != nil {
+			emit(line, nil, RowError{Row: line, Column: mapping.Year, Message: err.Error()})
+			continue
+		}
+		attribution, err := jsonFlag(fields[strings.ToLower(mapping.AttributionRequired)])
+		if err != nil {
+			emit(line, nil, RowError{Row: line, Column: mapping.AttributionRequired, Message: err.Error()})
+			continue
+		}
+
+		q := &entity.Quote{
+			Text:                text,
+			Tags:                jsonTags(fields[strings.ToLower(mapping.Tags)]),
+			Lang:                langOrDefault(str(mapping.Lang)),
+			Author:              str(mapping.Author),
+			ExternalId:          str(mapping.Id),
+			Work:                str(mapping.Work),
+			Year:                year,
+			License:             str(mapping.License),
+			AttributionRequired: attribution,
+		}
+		// NDJSON rows have keys rather than columns
+		if rowErrs := validateRow("", line, q, func(field string) string { return ndjsonKey(mapping, field) }); rowErrs != nil {
+			emit(line, nil, rowErrs...)
+			continue
+		}
+		emit(line, q)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line+1, err)
+	}
+	return nil
+}
+
+// ndjsonKey returns the key a quote field is read from in an NDJSON row.
+func ndjsonKey(mapping entity.ColumnMapping, field string) string {
+	switch field {
+	case "text":
+		return mapping.Text
+	case "tags":
+		return mapping.Tags
+	case "lang":
+		return mapping.Lang
+	case "author":
+		return mapping.Author
+	case "work":
+		return mapping.Work
+	case "year":
+		return mapping.Year
+	case "license":
+		return mapping.License
+	case "attributionRequired":
+		return mapping.AttributionRequired
+	}
+	return ""
+}
+
+func jsonTags(v interface{}) []string {
+	switch tags := v.(type) {
+	case string:
+		return processTags(tags)
+	case []interface{}:
+		out := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+		return out
+	}
+	return []string{}
+}
+
+// jsonFlag reads a boolean, or a yes/no string as parseFlag does.
+func jsonFlag(v interface{}) (bool, error) {
+	switch flag := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return flag, nil
+	case string:
+		return parseFlag(strings.TrimSpace(flag))
+	}
+	return false, fmt.Errorf("invalid yes/no value %v", v)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func langOrDefault(lang string) string {
+	if lang == "" {
+		return defaultLang
+	}
+	return lang
+}
@@ -0,0 +1,578 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+type fakeQuoteRepo struct {
+	quotes  []entity.Quote
+	batches int
+	// reject makes stores of this text fail like a constraint violation.
+	reject string
+	// bandErr fails near-duplicate lookups, which are counted in
+	// bandLookups.
+	bandErr     error
+	bandLookups int
+}
+
+// Store keeps explicit IDs, padding the gap with deleted slots.
+func (r *fakeQuoteRepo) Store(q *entity.Quote) (int, error) {
+	if r.reject != "" && q.Text == r.reject {
+		return 0, errors.New("check constraint violated")
+	}
+	if q.Id == 0 {
+		q.Id = len(r.quotes) + 1
+	}
+	for len(r.quotes) < q.Id {
+		r.quotes = append(r.quotes, entity.Quote{})
+	}
+	r.quotes[q.Id-1] = *q
+	return q.Id, nil
+}
+
+func (r *fakeQuoteRepo) StoreBatch(quotes []entity.Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+	r.batches++
+	for i := range quotes {
+		if _, err := r.Store(&quotes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Transaction restores the stored quotes when fn fails.
+func (r *fakeQuoteRepo) Transaction(fn func(repository.QuoteRepository) error) error {
+	saved := append([]entity.Quote(nil), r.quotes...)
+	if err := fn(r); err != nil {
+		r.quotes = saved
+		return err
+	}
+	return nil
+}
+
+func (r *fakeQuoteRepo) Update(q *entity.Quote) error {
+	r.quotes[q.Id-1] = *q
+	return nil
+}
+
+func (r *fakeQuoteRepo) UpdateVersion(q *entity.Quote, version int) error {
+	if r.quotes[q.Id-1].Version != version {
+		return repository.ErrVersionConflict
+	}
+	q.Version = version + 1
+	r.quotes[q.Id-1] = *q
+	return nil
+}
+
+func (r *fakeQuoteRepo) Delete(id int, version int) error {
+	if id > len(r.quotes) || r.quotes[id-1].Id == 0 {
+		return repository.ErrNotFound
+	}
+	if version > 0 && r.quotes[id-1].Version != version {
+		return repository.ErrVersionConflict
+	}
+	r.quotes[id-1] = entity.Quote{}
+	return nil
+}
+
+func (r *fakeQuoteRepo) FindByID(id int) (*entity.Quote, error) {
+	if id > len(r.quotes) || r.quotes[id-1].Id == 0 {
+		return nil, repository.ErrNotFound
+	}
+	q := r.quotes[id-1]
+	return &q, nil
+}
+
+func (r *fakeQuoteRepo) FindByIDs(ids []int) ([]entity.Quote, error) {
+	var found []entity.Quote
+	for _, id := range ids {
+		if id <= len(r.quotes) && r.quotes[id-1].Id != 0 {
+			found = append(found, r.quotes[id-1])
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeQuoteRepo) Restore(id int) error { return nil }
+
+func (r *fakeQuoteRepo) SyncIDSequence() error { return nil }
+
+func (r *fakeQuoteRepo) FindAll() ([]entity.Quote, error) {
+	var live []entity.Quote
+	for _, q := range r.quotes {
+		if q.Id != 0 {
+			live = append(live, q)
+		}
+	}
+	return live, nil
+}
+
+// List pages by ID; the cursor is the last ID as text.
+func (r *fakeQuoteRepo) List(filter repository.QuoteFilter) ([]entity.Quote, string, error) {
+	after := 0
+	if filter.Cursor != "" {
+		n, err := strconv.Atoi(filter.Cursor)
+		if err != nil {
+			return nil, "", repository.ErrInvalidCursor
+		}
+		after = n
+	}
+	var page []entity.Quote
+	for _, q := range r.quotes {
+		if q.Id <= after || q.Id == 0 {
+			continue
+		}
+		if filter.Author != "" && !strings.EqualFold(q.Author, filter.Author) {
+			continue
+		}
+		if filter.AttributionRequired != nil && q.AttributionRequired != *filter.AttributionRequired {
+			continue
+		}
+		if len(page) == filter.Limit {
+			return page, strconv.Itoa(page[len(page)-1].Id), nil
+		}
+		page = append(page, q)
+	}
+	return page, "", nil
+}
+
+// Search matches words case-insensitively, ranking by how many of the query
+// words a quote contains; the cursor is the offset as text.
+func (r *fakeQuoteRepo) Search(search repository.QuoteSearch) ([]repository.QuoteSearchHit, string, error) {
+	var hits []repository.QuoteSearchHit
+	words := strings.Fields(strings.ToLower(search.Query))
+	for _, q := range r.quotes {
+		if q.Id == 0 || (search.Lang != "" && q.Lang != search.Lang) {
+			continue
+		}
+		if search.Tag != "" && !containsTag(q.Tags, search.Tag) {
+			continue
+		}
+		rank := 0
+		for _, w := range words {
+			if strings.Contains(strings.ToLower(q.Text), w) {
+				rank++
+			}
+		}
+		if rank > 0 {
+			hits = append(hits, repository.QuoteSearchHit{Quote: q, Rank: float64(rank), Snippet: q.Text})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Rank > hits[j].Rank })
+
+	offset := 0
+	if search.Cursor != "" {
+		n, err := strconv.Atoi(search.Cursor)
+		if err != nil {
+			return nil, "", repository.ErrInvalidCursor
+		}
+		offset = n
+	}
+	if offset > len(hits) {
+		offset = len(hits)
+	}
+	hits = hits[offset:]
+	if len(hits) > search.Limit {
+		return hits[:search.Limit], strconv.Itoa(offset + search.Limit), nil
+	}
+	return hits, "", nil
+}
+
+func (r *fakeQuoteRepo) CountSearch(search repository.QuoteSearch) (int64, error) {
+	search.Cursor, search.Limit = "", len(r.quotes)
+	hits, _, err := r.Search(search)
+	return int64(len(hits)), err
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeQuoteRepo) FindByNaturalKeys(keys []string) ([]entity.Quote, error) {
+	var found []entity.Quote
+	for _, q := range r.quotes {
+		for _, key := range keys {
+			if q.Id != 0 && q.NaturalKey == key {
+				found = append(found, q)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeQuoteRepo) FindBySource(source string) ([]entity.Quote, error) {
+	var found []entity.Quote
+	for _, q := range r.quotes {
+		if q.Id != 0 && q.ImportSource == source {
+			found = append(found, q)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeQuoteRepo) FindByBands(bands []int64, langs []string) ([]entity.Quote, error) {
+	r.bandLookups++
+	var found []entity.Quote
+	for _, q := range r.quotes {
+		if q.Id == 0 || !containsFold(langs, q.Lang) {
+			continue
+		}
+		for _, b := range q.Bands {
+			if containsBand(bands, b) {
+				found = append(found, q)
+				break
+			}
+		}
+	}
+	return found, r.bandErr
+}
+
+func containsBand(bands []int64, band int64) bool {
+	for _, b := range bands {
+		if b == band {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeQuoteRepo) FindUnbanded(limit int) ([]entity.Quote, error) {
+	var found []entity.Quote
+	for _, q := range r.quotes {
+		if q.Id != 0 && q.Bands == nil && len(found) < limit {
+			found = append(found, q)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeQuoteRepo) SetFingerprints(quotes []entity.Quote) error {
+	for _, q := range quotes {
+		r.quotes[q.Id-1].Fingerprint, r.quotes[q.Id-1].Bands = q.Fingerprint, q.Bands
+	}
+	return nil
+}
+
+func (r *fakeQuoteRepo) FindByTranslationGroup(group string) ([]entity.Quote, error) {
+	var found []entity.Quote
+	for _, q := range r.quotes {
+		if q.Id != 0 && q.TranslationGroup == group {
+			found = append(found, q)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeQuoteRepo) TouchImportRun(keys []string, run string) error {
+	for i := range r.quotes {
+		for _, key := range keys {
+			if r.quotes[i].NaturalKey == key {
+				r.quotes[i].ImportRun = run
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteStale blanks deleted quotes in place so IDs stay valid indexes.
+func (r *fakeQuoteRepo) DeleteStale(source, run string) (int64, error) {
+	var n int64
+	for i, q := range r.quotes {
+		if q.Id != 0 && q.ImportSource == source && q.ImportRun != run {
+			r.quotes[i] = entity.Quote{}
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeMetadata struct {
+	quoteUpdates int
+	daily        []entity.DailyQuote
+}
+
+func (m *fakeMetadata) UpdateImageMetadata([]entity.Flyer) error { return nil }
+
+func (m *fakeMetadata) UpdateDailyQuoteMetadata(days []entity.DailyQuote) error {
+	m.daily = days
+	return nil
+}
+
+func (m *fakeMetadata) UpdateQuoteMetadata([]entity.Quote) error {
+	m.quoteUpdates++
+	return nil
+}
+
+func TestImportStreamCSV(t *testing.T) {
+	t.Setenv("BATCH_SIZE_QUOTE", "2")
+
+	body := "Quote;Categories;Language\n" +
+		"Stay hungry.;life,work;en-US\n" +
+		";ignored;en-US\n" +
+		"Sé valiente.;courage;es-ES\n" +
+		"\n" +
+		"No lang given.;;\n"
+	utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	uc := NewQuoteUseCase(repo, nil, nil, meta)
+	result, err := uc.ImportStream(strings.NewReader(utf16), StreamOptions{
+		Format:    FormatCSV,
+		Delimiter: ';',
+		Mapping:   entity.ColumnMapping{Text: "Quote", Tags: "Categories", Lang: "Language"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Total != 4 || result.Created != 3 || result.Failed != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 3 || result.Errors[0].Column != "A" {
+		t.Errorf("unexpected row errors: %+v", result.Errors)
+	}
+	if repo.batches != 2 {
+		t.Errorf("expected 2 batches, got %d", repo.batches)
+	}
+	if repo.quotes[1].Text != "Sé valiente." || repo.quotes[1].Lang != "es-ES" {
+		t.Errorf("unexpected decoded quote: %+v", repo.quotes[1])
+	}
+	if repo.quotes[2].Lang != defaultLang {
+		t.Errorf("expected default lang, got %q", repo.quotes[2].Lang)
+	}
+	if meta.quoteUpdates != 1 {
+		t.Errorf("expected metadata to be republished once, got %d", meta.quoteUpdates)
+	}
+}
+
+func TestImportStreamNDJSON(t *testing.T) {
+	body := bytes.NewBufferString("\xef\xbb\xbf" +
+		`{"text": "One", "tags": ["a", "b"]}` + "\n" +
+		`{"text": ""}` + "\n" +
+		`not json` + "\n" +
+		`{"TEXT": "Two", "tags": "c, d", "lang": "fr-FR"}` + "\n")
+
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	result, err := uc.ImportStream(body, StreamOptions{Format: FormatNDJSON})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Created != 2 || result.Failed != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(repo.quotes[1].Tags) != 2 || repo.quotes[1].Lang != "fr-FR" {
+		t.Errorf("unexpected second quote: %+v", repo.quotes[1])
+	}
+}
+
+func TestImportStreamUpsert(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	opts := StreamOptions{Format: FormatCSV, ImportOptions: ImportOptions{Source: "feed", Mirror: true}}
+
+	first := "id,text,tags\n" +
+		"a,Stay hungry.,life\n" +
+		"b,Stay foolish.,life\n" +
+		",Be kind.,\n" +
+		",  be   KIND. ,\n"
+	result, err := uc.ImportStream(strings.NewReader(first), opts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 3 || result.Failed != 1 || result.Errors[0].Message != "duplicate of row 4" {
+		t.Errorf("unexpected first result: %+v", result)
+	}
+
+	second := "id,text,tags\n" +
+		"a,Stay hungry.,life\n" +
+		"b,Stay foolish!,life\n" +
+		",New one.,\n"
+	result, err = uc.ImportStream(strings.NewReader(second), opts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 || result.Unchanged != 1 || result.Deleted != 1 {
+		t.Errorf("unexpected second result: %+v", result)
+	}
+	if repo.quotes[1].Text != "Stay foolish!" {
+		t.Errorf("expected quote b to be updated in place, got %+v", repo.quotes[1])
+	}
+	if live, _ := repo.FindAll(); len(live) != 3 {
+		t.Errorf("expected 3 quotes after mirroring, got %d", len(live))
+	}
+}
+
+func TestImportStreamMirrorRequiresSource(t *testing.T) {
+	uc := NewQuoteUseCase(&fakeQuoteRepo{}, nil, nil, &fakeMetadata{})
+	_, err := uc.ImportStream(strings.NewReader("text\nx\n"), StreamOptions{Format: FormatCSV, ImportOptions: ImportOptions{Mirror: true}})
+	if !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("expected ErrInvalidImport, got %v", err)
+	}
+}
+
+func TestImportStreamPolicies(t *testing.T) {
+	t.Setenv("BATCH_SIZE_QUOTE", "10")
+	body := "text,tags\nOne,a\nBad,\nTwo,\n,orphan\n"
+
+	repo := &fakeQuoteRepo{reject: "Bad"}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	result, err := uc.ImportStream(strings.NewReader(body), StreamOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 2 || result.Failed != 2 || len(result.Errors) != 2 || result.RolledBack {
+		t.Errorf("unexpected skip-bad-rows result: %+v", result)
+	}
+	if result.Errors[0].Row != 5 || result.Errors[1].Row != 3 {
+		t.Errorf("expected the empty row 5 and the constraint failure on row 3, got %+v", result.Errors)
+	}
+	if len(repo.quotes) != 2 {
+		t.Errorf("expected 2 stored quotes, got %d", len(repo.quotes))
+	}
+
+	repo = &fakeQuoteRepo{reject: "Bad"}
+	uc = NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	result, err = uc.ImportStream(strings.NewReader(body), StreamOptions{
+		Format:        FormatCSV,
+		ImportOptions: ImportOptions{Policy: PolicyAllOrNothing},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.RolledBack || result.Created != 0 || len(result.Errors) != 2 {
+		t.Errorf("unexpected all-or-nothing result: %+v", result)
+	}
+	if len(repo.quotes) != 0 {
+		t.Errorf("expected nothing stored, got %d quotes", len(repo.quotes))
+	}
+}
+
+func TestImportStreamCapsErrors(t *testing.T) {
+	body := "text,lang\n" + strings.Repeat(",en-US\n", maxReportedErrors+5)
+	uc := NewQuoteUseCase(&fakeQuoteRepo{}, nil, nil, &fakeMetadata{})
+	result, err := uc.ImportStream(strings.NewReader(body), StreamOptions{
+		Format:        FormatCSV,
+		ImportOptions: ImportOptions{DryRun: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed != maxReportedErrors+5 || len(result.Errors) != maxReportedErrors || len(result.Diff.Skip) != maxReportedErrors {
+		t.Errorf("expected %d errors listed out of %d, got %d listed, %d skipped, %d failed",
+			maxReportedErrors, maxReportedErrors+5, len(result.Errors), len(result.Diff.Skip), result.Failed)
+	}
+}
+
+func TestImportStreamValidatesRows(t *testing.T) {
+	body := "text,tags,lang\n" +
+		"Be kind.,life,en-US\n" +
+		"Be brave.,#courage,en-US\n" +
+		"Be calm.,#calm,english\n"
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	result, err := uc.ImportStream(strings.NewReader(body), StreamOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 1 || result.Failed != 2 || len(repo.quotes) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	// one error per failing field, pointing at the field's column
+	want := []struct {
+		row    int
+		column string
+		prefix string
+	}{{3, "B", "tags[0]:"}, {4, "B", "tags[0]:"}, {4, "C", "lang:"}}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("expected %d row errors, got %+v", len(want), result.Errors)
+	}
+	for i, w := range want {
+		got := result.Errors[i]
+		if got.Row != w.row || got.Column != w.column || !strings.HasPrefix(got.Message, w.prefix) {
+			t.Errorf("error %d: expected %s on row %d column %s, got %+v", i, w.prefix, w.row, w.column, got)
+		}
+	}
+}
+
+func TestImportStreamDryRunAllOrNothing(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	result, err := uc.ImportStream(strings.NewReader("text,tags\nOne,a\n,orphan\n"), StreamOptions{
+		Format:        FormatCSV,
+		ImportOptions: ImportOptions{Policy: PolicyAllOrNothing, DryRun: true},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.RolledBack || result.Created != 0 || result.Failed != 1 {
+		t.Errorf("expected the dry run to report a rollback, got %+v", result)
+	}
+	if len(result.Diff.Create) != 0 || len(result.Diff.Skip) != 1 {
+		t.Errorf("expected only the rejected row in the diff, got %+v", result.Diff)
+	}
+}
+
+func TestImportStreamAttribution(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+
+	csvBody := "text,author,source,year,license,credit\n" +
+		"Stay hungry.,Steve Jobs,Stanford commencement,2005,CC-BY-4.0,yes\n" +
+		"Be kind.,,,,,\n" +
+		"Too late.,,,3000,,\n" +
+		"Maybe.,,,,,sometimes\n"
+	result, err := uc.ImportStream(strings.NewReader(csvBody), StreamOptions{
+		Format:  FormatCSV,
+		Mapping: entity.ColumnMapping{Work: "source", AttributionRequired: "credit"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 2 || result.Failed != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	q := repo.quotes[0]
+	if q.Author != "Steve Jobs" || q.Work != "Stanford commencement" || q.Year != 2005 || q.License != "CC-BY-4.0" || !q.AttributionRequired {
+		t.Errorf("unexpected attribution: %+v", q)
+	}
+	if result.Errors[0].Column != "D" || result.Errors[1].Column != "F" {
+		t.Errorf("expected the year and credit columns to be blamed, got %+v", result.Errors)
+	}
+
+	ndjson := `{"text": "Simplicity.", "author": "Leonardo", "year": 1500, "attributionRequired": true}` + "\n"
+	if _, err := uc.ImportStream(strings.NewReader(ndjson), StreamOptions{Format: FormatNDJSON}); err != nil {
+		t.Fatal(err)
+	}
+	if q := repo.quotes[2]; q.Year != 1500 || !q.AttributionRequired {
+		t.Errorf("unexpected NDJSON attribution: %+v", q)
+	}
+
+	required := true
+	page, next, err := uc.ListQuotes(repository.QuoteFilter{Author: " steve jobs", AttributionRequired: &required})
+	if err != nil || len(page) != 1 || page[0].Text != "Stay hungry." || next != "" {
+		t.Errorf("expected the author filter to find one quote, got %+v, %q, %v", page, next, err)
+	}
+}
@@ -0,0 +1,314 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minSyncInterval keeps a mistyped schedule from hammering the Sheets API.
+const minSyncInterval = time.Minute
+
+// SyncUseCase keeps registered Google Sheets in sync with the quotes. A
+// background loop started with Start runs each source on its schedule;
+// sources whose contents are unchanged since the last run are skipped.
+type SyncUseCase struct {
+	quotes  *QuoteUseCase
+	sources repository.SyncSourceRepository
+	// mu serialises runs so the scheduler and manual runs never import the
+	// same sheet twice at once.
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+func NewSyncUseCase(quotes *QuoteUseCase, sources repository.SyncSourceRepository) *SyncUseCase {
+	return &SyncUseCase{
+		quotes:  quotes,
+		sources: sources,
+		now:     time.Now,
+	}
+}
+
+// AddSource validates and registers a sync source. Its first run is due
+// immediately.
+func (s *SyncUseCase) AddSource(source *entity.SyncSource) error {
+	source.Id = 0
+	if _, err := extractSpreadsheetID(source.SheetURL); err != nil {
+		return err
+	}
+	if _, err := parseSchedule(source.Schedule); err != nil {
+		return err
+	}
+	source.ContentHash, source.LastStatus, source.LastError = "", "", ""
+	source.LastRunAt, source.LastResult = nil, nil
+	source.NextRunAt = s.now()
+
+	id, err := s.sources.Store(source)
+	if err != nil {
+		return fmt.Errorf("failed to store sync source: %w", err)
+	}
+	source.Id = id
+	return nil
+}
+
+// ListSources returns every sync source with its last-run status.
+func (s *SyncUseCase) ListSources() ([]entity.SyncSource, error) {
+	return s.sources.FindAll()
+}
+
+// GetSource returns one sync source, or repository.ErrNotFound.
+func (s *SyncUseCase) GetSource(id int) (*entity.SyncSource, error) {
+	return s.sources.FindByID(id)
+}
+
+// RemoveSource unregisters a sync source. Quotes it imported are kept. It
+// waits for a run in progress, so the run cannot record itself on a source
+// that no longer exists.
+func (s *SyncUseCase) RemoveSource(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sources.Delete(id)
+}
+
+// SetPaused pauses or resumes a sync source. A resumed source that missed
+// runs while paused is due at once.
+func (s *SyncUseCase) SetPaused(id int, paused bool) (*entity.SyncSource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.sources.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	source.Paused = paused
+	if err := s.sources.Update(source); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// RunSource syncs one source now, regardless of its schedule.
+func (s *SyncUseCase) RunSource(id int) (*entity.SyncSource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.sources.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return source, s.run(source)
+}
+
+// RunDue syncs every source whose next run has come.
+func (s *SyncUseCase) RunDue() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due, err := s.sources.FindDue(s.now())
+	if err != nil {
+		return fmt.Errorf("failed to load due sync sources: %w", err)
+	}
+	for i := range due {
+		if err := s.run(&due[i]); err != nil {
+			log.Printf("Failed to record sync of source %d: %v", due[i].Id, err)
+		}
+	}
+	return nil
+}
+
+// Start runs due sources every interval until ctx is cancelled.
+func (s *SyncUseCase) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(); err != nil {
+			log.Printf("Sheets sync: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run pulls the sheet, reconciles it into the quotes unless its content
+// hash is unchanged, and records the outcome on the source. Only failing to
+// save the source is returned; sync failures are recorded on it.
+func (s *SyncUseCase) run(source *entity.SyncSource) error {
+	started := s.now()
+	hash, result, err := s.sync(source)
+
+	source.LastRunAt = &started
+	source.LastError = ""
+	switch {
+	case err != nil:
+		source.LastStatus = entity.SyncStatusFailed
+		source.LastError = err.Error()
+		log.Printf("Sheets sync of source %d failed: %v", source.Id, err)
+	case result == nil:
+		source.LastStatus = entity.SyncStatusUnchanged
+	case result.RolledBack:
+		source.LastStatus = entity.SyncStatusFailed
+		source.LastError = fmt.Sprintf("%d rows failed; nothing was imported", result.Failed)
+	default:
+		source.LastStatus = entity.SyncStatusOK
+		if result.Failed > 0 {
+			source.LastStatus = entity.SyncStatusPartial
+			source.LastError = fmt.Sprintf("%d rows failed", result.Failed)
+		} else {
+			// a partial import is retried on the next run, even if the
+			// sheet is not edited in between
+			source.ContentHash = hash
+		}
+		source.LastResult = &entity.SyncRunResult{
+			Total:      result.Total,
+			Created:    result.Created,
+			Updated:    result.Updated,
+			Unchanged:  result.Unchanged,
+			Deleted:    result.Deleted,
+			Failed:     result.Failed,
+			DurationMs: s.now().Sub(started).Milliseconds(),
+		}
+	}
+
+	interval, err := parseSchedule(source.Schedule)
+	if err != nil {
+		// the schedule was valid when stored; keep the source alive anyway
+		interval = time.Hour
+	}
+	source.NextRunAt = started.Add(interval)
+	return s.sources.Update(source)
+}
+
+// sync reads the source into a snapshot and imports it. It returns a nil
+// result when the snapshot matches the last imported content.
+func (s *SyncUseCase) sync(source *entity.SyncSource) (string, *ImportResult, error) {
+	req := SheetImportRequest{
+		ImportOptions:    ImportOptions{Mirror: source.Mirror},
+		GoogleSheetsLink: source.SheetURL,
+		Range:            source.Range,
+		Profile:          source.Profile,
+		AllTabs:          source.AllTabs,
+	}
+	spreadsheetID, err := extractSpreadsheetID(req.GoogleSheetsLink)
+	if err != nil {
+		return "", nil, err
+	}
+	readRange, mapping, err := s.quotes.resolveProfile(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	snap, err := s.snapshot(spreadsheetID, readRange, req.AllTabs)
+	if err != nil {
+		return "", nil, err
+	}
+	hash, err := snap.hash(mapping)
+	if err != nil {
+		return "", nil, err
+	}
+	if hash == source.ContentHash {
+		return hash, nil, nil
+	}
+
+	result, err := s.quotes.importSheet(snap, req)
+	if err != nil {
+		return "", nil, err
+	}
+	return hash, result, nil
+}
+
+// snapshot reads everything an import of the sheet would read.
+func (s *SyncUseCase) snapshot(spreadsheetID, readRange string, allTabs bool) (*sheetSnapshot, error) {
+	sheets := s.quotes.sheetsService
+	snap := &sheetSnapshot{values: make(map[string][][]interface{})}
+
+	ranges := []string{readRange}
+	if allTabs {
+		titles, err := sheets.SheetTitles(spreadsheetID)
+		if err != nil {
+			return nil, err
+		}
+		snap.titles = titles
+		ranges = ranges[:0]
+		for _, title := range titles {
+			ranges = append(ranges, quoteSheetTitle(title))
+		}
+	}
+
+	for _, r := range ranges {
+		rows, err := sheets.ReadRange(spreadsheetID, r)
+		if err != nil {
+			return nil, err
+		}
+		snap.values[r] = rows
+	}
+	return snap, nil
+}
+
+// sheetSnapshot serves a spreadsheet read ahead of an import, so the import
+// sees exactly the content that was hashed.
+type sheetSnapshot struct {
+	titles []string
+	values map[string][][]interface{}
+}
+
+func (s *sheetSnapshot) ReadRange(spreadsheetID, readRange string) ([][]interface{}, error) {
+	return s.values[readRange], nil
+}
+
+func (s *sheetSnapshot) SheetTitles(spreadsheetID string) ([]string, error) {
+	return s.titles, nil
+}
+
+// hash digests the snapshot together with the column mapping, so editing a
+// profile also triggers a new import. encoding/json sorts map keys, so equal
+// contents always hash the same.
+func (s *sheetSnapshot) hash(mapping entity.ColumnMapping) (string, error) {
+	data, err := json.Marshal(struct {
+		Titles  []string                   `json:"titles"`
+		Values  map[string][][]interface{} `json:"values"`
+		Mapping entity.ColumnMapping       `json:"mapping"`
+	}{s.titles, s.values, mapping})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash sheet contents: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// parseSchedule accepts a Go duration or one of "@every <duration>",
+// "@hourly", "@daily" and "@weekly".
+func parseSchedule(schedule string) (time.Duration, error) {
+	schedule = strings.TrimSpace(schedule)
+	var interval time.Duration
+	switch schedule {
+	case "@hourly":
+		interval = time.Hour
+	case "@daily":
+		interval = 24 * time.Hour
+	case "@weekly":
+		interval = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(schedule, "@every")))
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid schedule %q", ErrInvalidImport, schedule)
+		}
+		interval = d
+	}
+	if interval < minSyncInterval {
+		return 0, fmt.Errorf("%w: schedule %q is shorter than %s", ErrInvalidImport, schedule, minSyncInterval)
+	}
+	return interval, nil
+}
@@ -0,0 +1,206 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"testing"
+	"time"
+)
+
+type fakeSyncRepo struct {
+	sources map[int]*entity.SyncSource
+}
+
+func (r *fakeSyncRepo) Store(s *entity.SyncSource) (int, error) {
+	s.Id = len(r.sources) + 1
+	copied := *s
+	r.sources[s.Id] = &copied
+	return s.Id, nil
+}
+
+func (r *fakeSyncRepo) Update(s *entity.SyncSource) error {
+	if _, ok := r.sources[s.Id]; !ok {
+		return repository.ErrNotFound
+	}
+	copied := *s
+	r.sources[s.Id] = &copied
+	return nil
+}
+
+func (r *fakeSyncRepo) Delete(id int) error {
+	delete(r.sources, id)
+	return nil
+}
+
+func (r *fakeSyncRepo) FindByID(id int) (*entity.SyncSource, error) {
+	s, ok := r.sources[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *fakeSyncRepo) FindAll() ([]entity.SyncSource, error) {
+	var all []entity.SyncSource
+	for _, s := range r.sources {
+		all = append(all, *s)
+	}
+	return all, nil
+}
+
+func (r *fakeSyncRepo) FindDue(now time.Time) ([]entity.SyncSource, error) {
+	var due []entity.SyncSource
+	for _, s := range r.sources {
+		if !s.Paused && !s.NextRunAt.After(now) {
+			due = append(due, *s)
+		}
+	}
+	return due, nil
+}
+
+func TestSyncSkipsUnchangedSheets(t *testing.T) {
+	sheets := &fakeSheets{values: map[string][][]interface{}{
+		"English": {{"text"}, {"Be kind."}},
+	}}
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	sources := &fakeSyncRepo{sources: map[int]*entity.SyncSource{}}
+	uc := NewSyncUseCase(NewQuoteUseCase(repo, nil, sheets, meta), sources)
+
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	source := &entity.SyncSource{SheetURL: "https://docs.google.com/spreadsheets/d/abc/edit", Schedule: "@hourly"}
+	if err := uc.AddSource(source); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := uc.RunDue(); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := sources.FindByID(source.Id)
+	if got.LastStatus != entity.SyncStatusOK || got.LastResult.Created != 1 || !got.NextRunAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected first run: %+v", got)
+	}
+
+	// not due yet
+	if err := uc.RunDue(); err != nil {
+		t.Fatal(err)
+	}
+	if meta.quoteUpdates != 1 {
+		t.Errorf("expected one metadata publish, got %d", meta.quoteUpdates)
+	}
+
+	now = now.Add(time.Hour)
+	if err := uc.RunDue(); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = sources.FindByID(source.Id)
+	if got.LastStatus != entity.SyncStatusUnchanged || meta.quoteUpdates != 1 {
+		t.Errorf("expected an unchanged sheet to be skipped, got %+v", got)
+	}
+
+	sheets.values["English"] = append(sheets.values["English"], []interface{}{"Stay curious."})
+	if _, err := uc.RunSource(source.Id); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = sources.FindByID(source.Id)
+	if got.LastStatus != entity.SyncStatusOK || got.LastResult.Created != 1 || got.LastResult.Unchanged != 1 || meta.quoteUpdates != 2 {
+		t.Errorf("unexpected run after an edit: %+v", got)
+	}
+}
+
+func TestSyncRetriesPartialRuns(t *testing.T) {
+	sheets := &fakeSheets{values: map[string][][]interface{}{
+		"English": {{"text", "tags"}, {"Be kind.", "kindness"}, {"Stay curious.", "!curiosity"}},
+	}}
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	sources := &fakeSyncRepo{sources: map[int]*entity.SyncSource{}}
+	uc := NewSyncUseCase(NewQuoteUseCase(repo, nil, sheets, meta), sources)
+
+	source := &entity.SyncSource{SheetURL: "https://docs.google.com/spreadsheets/d/abc/edit", Schedule: "@hourly"}
+	if err := uc.AddSource(source); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got, err := uc.RunSource(source.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastStatus != entity.SyncStatusPartial || got.ContentHash != "" {
+		t.Fatalf("expected a partial run without a content hash, got %+v", got)
+	}
+
+	// the sheet is unchanged, but the failed row is still pending
+	got, err = uc.RunSource(source.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastStatus != entity.SyncStatusPartial || got.LastResult.Unchanged != 1 {
+		t.Errorf("expected the partial run to be retried, got %+v", got)
+	}
+}
+
+func TestSyncSkipsPausedSources(t *testing.T) {
+	sheets := &fakeSheets{values: map[string][][]interface{}{
+		"English": {{"text"}, {"Be kind."}},
+	}}
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	sources := &fakeSyncRepo{sources: map[int]*entity.SyncSource{}}
+	uc := NewSyncUseCase(NewQuoteUseCase(repo, nil, sheets, meta), sources)
+
+	source := &entity.SyncSource{SheetURL: "https://docs.google.com/spreadsheets/d/abc/edit", Schedule: "@hourly"}
+	if err := uc.AddSource(source); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := uc.SetPaused(source.Id, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.RunDue(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := sources.FindByID(source.Id); got.LastRunAt != nil || len(repo.quotes) != 0 {
+		t.Fatalf("expected a paused source not to run, got %+v", got)
+	}
+
+	got, err := uc.SetPaused(source.Id, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Paused {
+		t.Errorf("expected the source to be resumed")
+	}
+	if err := uc.RunDue(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := sources.FindByID(source.Id); got.LastStatus != entity.SyncStatusOK || len(repo.quotes) != 1 {
+		t.Errorf("expected a resumed source to run, got %+v", got)
+	}
+
+	if _, err := uc.SetPaused(source.Id+1, true); err != repository.ErrNotFound {
+		t.Errorf("expected ErrNotFound for an unknown source, got %v", err)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	for schedule, want := range map[string]time.Duration{
+		"30m":        30 * time.Minute,
+		"@every 2h":  2 * time.Hour,
+		"@daily":     24 * time.Hour,
+		" @hourly ":  time.Hour,
+		"10s":        0,
+		"every week": 0,
+	} {
+		got, err := parseSchedule(schedule)
+		if want == 0 {
+			if err == nil {
+				t.Errorf("expected %q to be rejected", schedule)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("parseSchedule(%q) = %v, %v; want %v", schedule, got, err, want)
+		}
+	}
+}
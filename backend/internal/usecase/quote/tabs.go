@@ -0,0 +1,128 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/service"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultTabLangs maps common tab names to BCP 47 codes. Entries from
+// SHEET_LANG_MAP and the request take precedence.
+var defaultTabLangs = map[string]string{
+	"english":    "en-US",
+	"hindi":      "hi-IN",
+	"tamil":      "ta-IN",
+	"telugu":     "te-IN",
+	"kannada":    "kn-IN",
+	"malayalam":  "ml-IN",
+	"marathi":    "mr-IN",
+	"bengali":    "bn-IN",
+	"gujarati":   "gu-IN",
+	"punjabi":    "pa-IN",
+	"urdu":       "ur-IN",
+	"spanish":    "es-ES",
+	"french":     "fr-FR",
+	"german":     "de-DE",
+	"portuguese": "pt-BR",
+	"arabic":     "ar-SA",
+	"japanese":   "ja-JP",
+	"chinese":    "zh-CN",
+}
+
+// bcp47Tag loosely matches tab names that already are language tags.
+var bcp47Tag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// SkippedSheet records a tab that was left out of an all-tabs import.
+type SkippedSheet struct {
+	Sheet  string `json:"sheet"`
+	Reason string `json:"reason"`
+}
+
+// importAllTabs imports every tab of a spreadsheet as its own language.
+// Quotes that carry the same ID in several tabs share a translation group.
+func (uc *QuoteUseCase) importAllTabs(sheets service.SheetsService, imp *importer, spreadsheetID string, mapping entity.ColumnMapping, langMap map[string]string) (*ImportResult, error) {
+	titles, err := sheets.SheetTitles(spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+	langs := tabLangTable(langMap)
+	imp.translations = true
+
+	return imp.execute(func() error {
+		for _, title := range titles {
+			lang := tabLang(title, langs)
+			if lang == "" {
+				imp.skipSheet(title, "no language mapping for tab")
+				continue
+			}
+
+			rows, err := sheets.ReadRange(spreadsheetID, quoteSheetTitle(title))
+			if err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				imp.skipSheet(title, "tab is empty")
+				continue
+			}
+
+			emit := imp.emit(title)
+			err = processRows(title, 1, rows, mapping, lang, func(row int, q *entity.Quote, rowErrs ...RowError) {
+				if q != nil && q.ExternalId != "" {
+					q.TranslationGroup = fmt.Sprintf("%s:%s", spreadsheetID, q.ExternalId)
+				}
+				emit(row, q, rowErrs...)
+			})
+			if err != nil {
+				var headerErr *HeaderError
+				if errors.As(err, &headerErr) {
+					// the tab's quotes are unknown, so mirroring must not delete them
+					imp.skipSheet(title, headerErr.Error())
+					imp.incomplete = true
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// tabLangTable merges the built-in table, SHEET_LANG_MAP ("Hindi=hi-IN,...")
+// and the per-request overrides, keyed by lower-cased tab name.
+func tabLangTable(overrides map[string]string) map[string]string {
+	table := make(map[string]string, len(defaultTabLangs))
+	for name, lang := range defaultTabLangs {
+		table[name] = lang
+	}
+	for _, pair := range strings.Split(os.Getenv("SHEET_LANG_MAP"), ",") {
+		name, lang, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(name) != "" && strings.TrimSpace(lang) != "" {
+			table[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(lang)
+		}
+	}
+	for name, lang := range overrides {
+		table[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(lang)
+	}
+	return table
+}
+
+// tabLang returns the language for a tab, or "" when it has none. Tabs named
+// with a language tag such as "hi-IN" map to themselves.
+func tabLang(title string, table map[string]string) string {
+	if lang, ok := table[strings.ToLower(strings.TrimSpace(title))]; ok {
+		return lang
+	}
+	if bcp47Tag.MatchString(title) {
+		return title
+	}
+	return ""
+}
+
+// quoteSheetTitle quotes a tab name for use as an A1 range.
+func quoteSheetTitle(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
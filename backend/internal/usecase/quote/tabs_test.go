@@ -0,0 +1,61 @@
+package quote
+
+import (
+	"testing"
+)
+
+type fakeSheets struct {
+	tabs   []string
+	values map[string][][]interface{}
+}
+
+func (s *fakeSheets) ReadRange(spreadsheetID, readRange string) ([][]interface{}, error) {
+	return s.values[readRange], nil
+}
+
+func (s *fakeSheets) SheetTitles(spreadsheetID string) ([]string, error) {
+	return s.tabs, nil
+}
+
+func TestImportAllTabs(t *testing.T) {
+	sheets := &fakeSheets{
+		tabs: []string{"English", "Hindi", "Notes", "ta-IN"},
+		values: map[string][][]interface{}{
+			"'English'": {{"id", "text"}, {"q1", "Be kind."}, {"q2", "Stay curious."}},
+			"'Hindi'":   {{"id", "text"}, {"q1", "दयालु बनो।"}},
+			"'ta-IN'":   {{"id", "text"}, {"", "அன்பாக இரு."}},
+		},
+	}
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, sheets, &fakeMetadata{})
+
+	result, err := uc.ImportFromSheet(SheetImportRequest{
+		GoogleSheetsLink: "https://docs.google.com/spreadsheets/d/sheet123/edit",
+		AllTabs:          true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Created != 4 {
+		t.Errorf("expected 4 quotes imported, got %+v", result)
+	}
+	if len(result.SkippedSheets) != 1 || result.SkippedSheets[0].Sheet != "Notes" {
+		t.Errorf("expected Notes to be skipped, got %+v", result.SkippedSheets)
+	}
+
+	byText := map[string]int{}
+	for i, q := range repo.quotes {
+		byText[q.Text] = i
+	}
+	en, hi, ta := repo.quotes[byText["Be kind."]], repo.quotes[byText["दयालु बनो।"]], repo.quotes[byText["அன்பாக இரு."]]
+	if hi.Lang != "hi-IN" || ta.Lang != "ta-IN" || en.Lang != "en-US" {
+		t.Errorf("unexpected languages: en=%s hi=%s ta=%s", en.Lang, hi.Lang, ta.Lang)
+	}
+	if en.TranslationGroup == "" || en.TranslationGroup != hi.TranslationGroup {
+		t.Errorf("expected q1 translations to share a group, got %q and %q", en.TranslationGroup, hi.TranslationGroup)
+	}
+	if ta.TranslationGroup != "" {
+		t.Errorf("expected no group for a row without ID, got %q", ta.TranslationGroup)
+	}
+}
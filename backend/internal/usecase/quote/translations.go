@@ -0,0 +1,186 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrTranslationConflict is returned when linking would put two quotes of
+// the same language in one translation group.
+var ErrTranslationConflict = errors.New("translation group already has a quote in this language")
+
+// Translations returns the other quotes in the quote's translation group.
+func (uc *QuoteUseCase) Translations(id int) ([]entity.Quote, error) {
+	q, err := uc.quoteRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if q.TranslationGroup == "" {
+		return []entity.Quote{}, nil
+	}
+	group, err := uc.quoteRepo.FindByTranslationGroup(q.TranslationGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load translations: %w", err)
+	}
+	siblings := []entity.Quote{}
+	for _, other := range group {
+		if other.Id != id {
+			siblings = append(siblings, other)
+		}
+	}
+	return siblings, nil
+}
+
+// LinkTranslation puts the quotes id and otherID, together with the groups
+// they already belong to, in one translation group and republishes the
+// quotes metadata. It returns the whole group.
+func (uc *QuoteUseCase) LinkTranslation(id, otherID int) ([]entity.Quote, error) {
+	if id == otherID {
+		return nil, fmt.Errorf("%w: a quote cannot be a translation of itself", ErrInvalidQuote)
+	}
+
+	var members []entity.Quote
+	err := uc.quoteRepo.Transaction(func(repo repository.QuoteRepository) error {
+		a, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		b, err := repo.FindByID(otherID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: quote %d does not exist", ErrInvalidQuote, otherID)
+		}
+		if err != nil {
+			return err
+		}
+		if a.TranslationGroup != "" && a.TranslationGroup == b.TranslationGroup {
+			members, err = repo.FindByTranslationGroup(a.TranslationGroup)
+			return err
+		}
+
+		if members, err = groupMembers(repo, a); err != nil {
+			return err
+		}
+		others, err := groupMembers(repo, b)
+		if err != nil {
+			return err
+		}
+		members = append(members, others...)
+
+		langs := make(map[string]int, len(members))
+		for _, q := range members {
+			if first, ok := langs[q.Lang]; ok {
+				return fmt.Errorf("%w: quotes %d and %d are both %s", ErrTranslationConflict, first, q.Id, q.Lang)
+			}
+			langs[q.Lang] = q.Id
+		}
+
+		group := a.TranslationGroup
+		if group == "" {
+			group = b.TranslationGroup
+		}
+		if group == "" {
+			if group, err = newTranslationGroup(); err != nil {
+				return err
+			}
+		}
+		for i := range members {
+			q := &members[i]
+			if q.TranslationGroup == group {
+				continue
+			}
+			q.TranslationGroup = group
+			if err := repo.UpdateVersion(q, q.Version); err != nil {
+				return fmt.Errorf("failed to link quote %d: %w", q.Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.updateMetadata(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// UnlinkTranslation takes a quote out of its translation group and
+// republishes the quotes metadata. A group left with a single quote is
+// dissolved.
+func (uc *QuoteUseCase) UnlinkTranslation(id int) error {
+	changed := false
+	err := uc.quoteRepo.Transaction(func(repo repository.QuoteRepository) error {
+		q, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if q.TranslationGroup == "" {
+			return nil
+		}
+		group, err := repo.FindByTranslationGroup(q.TranslationGroup)
+		if err != nil {
+			return fmt.Errorf("failed to load translations: %w", err)
+		}
+
+		unlink := []entity.Quote{*q}
+		if len(group) <= 2 {
+			unlink = group
+		}
+		for i := range unlink {
+			u := &unlink[i]
+			u.TranslationGroup = ""
+			if err := repo.UpdateVersion(u, u.Version); err != nil {
+				return fmt.Errorf("failed to unlink quote %d: %w", u.Id, err)
+			}
+		}
+		changed = true
+		return nil
+	})
+	if err != nil || !changed {
+		return err
+	}
+	return uc.updateMetadata()
+}
+
+// groupMembers returns the quotes in q's translation group, or just q.
+func groupMembers(repo repository.QuoteRepository, q *entity.Quote) ([]entity.Quote, error) {
+	if q.TranslationGroup == "" {
+		return []entity.Quote{*q}, nil
+	}
+	members, err := repo.FindByTranslationGroup(q.TranslationGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load translations: %w", err)
+	}
+	return members, nil
+}
+
+func newTranslationGroup() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate translation group: %w", err)
+	}
+	return "tg-" + hex.EncodeToString(b), nil
+}
+
+// attachTranslations fills in each quote's sibling translations.
+func attachTranslations(quotes []entity.Quote) {
+	groups := make(map[string][]entity.QuoteTranslation)
+	for _, q := range quotes {
+		if q.TranslationGroup != "" {
+			groups[q.TranslationGroup] = append(groups[q.TranslationGroup], entity.QuoteTranslation{Id: q.Id, Lang: q.Lang})
+		}
+	}
+	for i := range quotes {
+		q := &quotes[i]
+		q.Translations = nil
+		for _, t := range groups[q.TranslationGroup] {
+			if t.Id != q.Id {
+				q.Translations = append(q.Translations, t)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLinkAndUnlinkTranslations(t *testing.T) {
+	repo, meta := &fakeQuoteRepo{}, &fakeMetadata{}
+	uc := NewQuoteUseCase(repo, nil, nil, meta)
+	for _, q := range []entity.Quote{
+		{Text: "Be kind.", Lang: "en-US"},
+		{Text: "Sois gentil.", Lang: "fr-FR"},
+		{Text: "Sé amable.", Lang: "es-ES"},
+		{Text: "Be nice.", Lang: "en-US"},
+	} {
+		if _, err := uc.CreateQuote(&q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	group, err := uc.LinkTranslation(1, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(group) != 2 || group[0].TranslationGroup == "" || group[0].TranslationGroup != group[1].TranslationGroup {
+		t.Fatalf("expected quotes 1 and 2 to share a group, got %+v", group)
+	}
+	if _, err := uc.LinkTranslation(3, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.quotes[2].TranslationGroup != repo.quotes[0].TranslationGroup || repo.quotes[2].Version != 2 {
+		t.Errorf("expected quote 3 to join the existing group, got %+v", repo.quotes[2])
+	}
+	if _, err := uc.LinkTranslation(4, 1); !errors.Is(err, ErrTranslationConflict) {
+		t.Errorf("expected a language conflict, got %v", err)
+	}
+	if _, err := uc.LinkTranslation(1, 99); !errors.Is(err, ErrInvalidQuote) {
+		t.Errorf("expected an unknown quote to be rejected, got %v", err)
+	}
+
+	siblings, err := uc.Translations(2)
+	if err != nil || len(siblings) != 2 {
+		t.Fatalf("expected two siblings, got %+v, %v", siblings, err)
+	}
+
+	live, _ := repo.FindAll()
+	attachTranslations(live)
+	if got := live[0].Translations; len(got) != 2 || got[0] != (entity.QuoteTranslation{Id: 2, Lang: "fr-FR"}) {
+		t.Errorf("unexpected published translations: %+v", got)
+	}
+	if live[3].Translations != nil {
+		t.Errorf("expected an unlinked quote to have no translations, got %+v", live[3].Translations)
+	}
+
+	if err := uc.UnlinkTranslation(3); err != nil {
+		t.Fatal(err)
+	}
+	if repo.quotes[2].TranslationGroup != "" || repo.quotes[0].TranslationGroup == "" {
+		t.Errorf("expected only quote 3 to leave the group")
+	}
+	if err := uc.UnlinkTranslation(2); err != nil {
+		t.Fatal(err)
+	}
+	if repo.quotes[0].TranslationGroup != "" {
+		t.Errorf("expected the last member's group to be dissolved, got %q", repo.quotes[0].TranslationGroup)
+	}
+}
+
+func TestImportKeepsTranslationLinks(t *testing.T) {
+	repo := &fakeQuoteRepo{}
+	uc := NewQuoteUseCase(repo, nil, nil, &fakeMetadata{})
+	opts := StreamOptions{Format: FormatCSV, ImportOptions: ImportOptions{Source: "feed"}}
+	body := "id,text,lang\na,Be kind.,en-US\nb,Sois gentil.,fr-FR\n"
+	if _, err := uc.ImportStream(strings.NewReader(body), opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.LinkTranslation(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	group := repo.quotes[0].TranslationGroup
+
+	opts.DryRun = true
+	result, err := uc.ImportStream(strings.NewReader("id,text,lang\na,Be kind.,en-US\n"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Unchanged != 1 || result.Updated != 0 {
+		t.Errorf("expected the dry run to find nothing to change, got %+v", result)
+	}
+
+	opts.DryRun = false
+	result, err = uc.ImportStream(strings.NewReader("id,text,lang\na,Be kind!,en-US\n"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 || repo.quotes[0].Text != "Be kind!" {
+		t.Fatalf("expected quote a to be updated, got %+v", result)
+	}
+	if repo.quotes[0].TranslationGroup != group || repo.quotes[1].TranslationGroup != group {
+		t.Errorf("expected the re-import to keep the link, got %q and %q", repo.quotes[0].TranslationGroup, repo.quotes[1].TranslationGroup)
+	}
+}
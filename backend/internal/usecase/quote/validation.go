@@ -0,0 +1,169 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTextLength     = 1000
+	maxTags           = 20
+	maxTagLength      = 40
+	maxAttributionLen = 200
+	maxLicenseLength  = 64
+)
+
+// tagCharset allows letters, digits, spaces, hyphens and underscores, starting
+// with a letter or digit.
+var tagCharset = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}\p{M} _-]*$`)
+
+// FieldError names a field that failed validation by its JSON path, e.g.
+// "tags[2]" or "quotes[3].text". Rule is set for quote validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of a quote that broke a rule. It matches
+// ErrInvalidQuote under errors.Is.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidQuote, e.fields())
+}
+
+// fields lists the failures as "field: message" pairs.
+func (e *ValidationError) fields() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidQuote
+}
+
+// quoteRule checks one aspect of a quote, calling fail for every field that
+// breaks it.
+type quoteRule struct {
+	name  string
+	check func(q *entity.Quote, fail func(field, message string))
+}
+
+// quoteRules are applied in order to every quote created, edited or
+// imported. Optional fields are only checked when set.
+var quoteRules = []quoteRule{
+	{"required", func(q *entity.Quote, fail func(field, message string)) {
+		if q.Text == "" {
+			fail("text", "text is required")
+		}
+	}},
+	{"maxLength", func(q *entity.Quote, fail func(field, message string)) {
+		limits := []struct {
+			field, value string
+			max          int
+		}{
+			{"text", q.Text, maxTextLength},
+			{"author", q.Author, maxAttributionLen},
+			{"work", q.Work, maxAttributionLen},
+			{"license", q.License, maxLicenseLength},
+		}
+		for _, l := range limits {
+			if utf8.RuneCountInString(l.value) > l.max {
+				fail(l.field, fmt.Sprintf("must be at most %d characters", l.max))
+			}
+		}
+	}},
+	{"maxItems", func(q *entity.Quote, fail func(field, message string)) {
+		if msg := CheckTagCount(len(q.Tags)); msg != "" {
+			fail("tags", msg)
+		}
+	}},
+	{"tagFormat", func(q *entity.Quote, fail func(field, message string)) {
+		for i, tag := range q.Tags {
+			if msg := CheckTag(tag); msg != "" {
+				fail(fmt.Sprintf("tags[%d]", i), msg)
+			}
+		}
+	}},
+	{"langFormat", func(q *entity.Quote, fail func(field, message string)) {
+		switch {
+		case q.Lang == "":
+			fail("lang", "lang is required")
+		case !bcp47Tag.MatchString(q.Lang):
+			fail("lang", fmt.Sprintf("%q is not a language tag such as en-US", q.Lang))
+		}
+	}},
+	{"yearRange", func(q *entity.Quote, fail func(field, message string)) {
+		if q.Year > time.Now().Year() {
+			fail("year", fmt.Sprintf("%d is in the future", q.Year))
+		}
+	}},
+}
+
+// CheckTag returns why tag is not an acceptable tag, or "" when it is.
+func CheckTag(tag string) string {
+	switch {
+	case utf8.RuneCountInString(tag) > maxTagLength:
+		return fmt.Sprintf("must be at most %d characters", maxTagLength)
+	case !tagCharset.MatchString(tag):
+		return fmt.Sprintf("%q may only hold letters, digits, spaces, hyphens and underscores", tag)
+	}
+	return ""
+}
+
+// CheckTagCount returns why a quote may not carry n tags, or "" when it may.
+func CheckTagCount(n int) string {
+	if n > maxTags {
+		return fmt.Sprintf("at most %d tags are allowed, got %d", maxTags, n)
+	}
+	return ""
+}
+
+// ValidateQuote trims the quote's fields, normalises its tags and checks it
+// against every rule, returning a *ValidationError listing all failures.
+func ValidateQuote(q *entity.Quote) error {
+	q.Text = strings.TrimSpace(q.Text)
+	q.Lang = strings.TrimSpace(q.Lang)
+	q.Author = strings.TrimSpace(q.Author)
+	q.Work = strings.TrimSpace(q.Work)
+	q.License = strings.TrimSpace(q.License)
+	q.Tags = normalizeTags(q.Tags)
+
+	var fields []FieldError
+	for _, rule := range quoteRules {
+		rule.check(q, func(field, message string) {
+			fields = append(fields, FieldError{Field: field, Rule: rule.name, Message: message})
+		})
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Errors: fields}
+	}
+	return nil
+}
+
+// validateRow checks an imported quote against the rules and returns one
+// RowError per failing field, with Column set through column, which maps a
+// field name such as "tags" to where the row holds it.
+func validateRow(sheet string, row int, q *entity.Quote, column func(field string) string) []RowError {
+	var validationErr *ValidationError
+	if err := ValidateQuote(q); !errors.As(err, &validationErr) {
+		return nil
+	}
+	errs := make([]RowError, len(validationErr.Errors))
+	for i, fe := range validationErr.Errors {
+		field, _, _ := strings.Cut(fe.Field, "[")
+		errs[i] = RowError{Sheet: sheet, Row: row, Column: column(field), Message: fe.Field + ": " + fe.Message}
+	}
+	return errs
+}
@@ -0,0 +1,55 @@
+package quote
+
+import (
+	"backend/internal/domain/entity"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateQuoteListsEveryFieldError(t *testing.T) {
+	q := &entity.Quote{
+		Text:   "  ",
+		Lang:   "English!",
+		Tags:   []string{"life", "#yolo", strings.Repeat("x", maxTagLength+1)},
+		Author: strings.Repeat("a", maxAttributionLen+1),
+		Year:   9999,
+	}
+	err := ValidateQuote(q)
+	if !errors.Is(err, ErrInvalidQuote) {
+		t.Fatalf("expected ErrInvalidQuote, got %v", err)
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got %T", err)
+	}
+	want := []string{"text:required", "author:maxLength", "tags[1]:tagFormat", "tags[2]:tagFormat", "lang:langFormat", "year:yearRange"}
+	var got []string
+	for _, fe := range validationErr.Errors {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestValidateQuoteNormalises(t *testing.T) {
+	tags := make([]string, maxTags+1)
+	for i := range tags {
+		tags[i] = "Tag " + string(rune('a'+i))
+	}
+	q := &entity.Quote{Text: " Sé valiente. ", Lang: "es-ES", Tags: append(tags[:maxTags:maxTags], " tag a ", "")}
+	if err := ValidateQuote(q); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if q.Text != "Sé valiente." || len(q.Tags) != maxTags {
+		t.Errorf("expected trimmed text and deduplicated tags, got %+v", q)
+	}
+
+	q.Tags = tags
+	var validationErr *ValidationError
+	if err := ValidateQuote(q); !errors.As(err, &validationErr) || validationErr.Errors[0].Rule != "maxItems" {
+		t.Errorf("expected a maxItems error, got %v", err)
+	}
+}
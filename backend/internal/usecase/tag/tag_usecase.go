@@ -0,0 +1,309 @@
+package tag
+
+import (
+	"backend/internal/domain/repository"
+	"backend/internal/usecase/quote"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bulk tag operations.
+const (
+	// OpRename replaces one tag with another.
+	OpRename = "rename"
+	// OpMerge replaces several tags with one.
+	OpMerge = "merge"
+	// OpDelete removes tags from every item carrying them.
+	OpDelete = "delete"
+	// OpAdd adds tags to the selected items.
+	OpAdd = "add"
+	// OpRemove removes tags from the selected items.
+	OpRemove = "remove"
+)
+
+// Scopes limiting an operation to one table. The zero value covers both.
+const (
+	ScopeQuotes = "quotes"
+	ScopeFlyers = "flyers"
+)
+
+// ErrInvalidOperation is returned when a bulk tag request fails validation.
+var ErrInvalidOperation = errors.New("invalid tag operation")
+
+// Filter narrows the items an operation touches, on top of the tags the
+// operation itself matches.
+type Filter struct {
+	Tag      string `json:"tag,omitempty"`
+	Lang     string `json:"lang,omitempty"`
+	QuoteIDs []int  `json:"quoteIds,omitempty"`
+	FlyerIDs []uint `json:"flyerIds,omitempty"`
+}
+
+// BulkRequest describes one bulk operation. Rename takes one tag in Tag or
+// Tags and the new name in To; merge takes the tags to fold in Tags and the
+// tag they become in To; delete, add and remove take Tags. Add and remove
+// need a filter, so they cannot touch every item by accident.
+type BulkRequest struct {
+	Op     string   `json:"-"`
+	Tag    string   `json:"tag,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	To     string   `json:"to,omitempty"`
+	Scope  string   `json:"scope,omitempty"`
+	Filter Filter   `json:"filter"`
+	DryRun bool     `json:"dryRun,omitempty"`
+}
+
+// BulkResult counts the quotes and flyers whose tags changed, or would
+// change in a dry run. OverLimit lists the quotes a dry run found would end
+// up with too many tags; outside a dry run they fail the operation.
+type BulkResult struct {
+	Op        string `json:"op"`
+	DryRun    bool   `json:"dryRun"`
+	Quotes    int    `json:"quotes"`
+	Flyers    int    `json:"flyers"`
+	OverLimit []int  `json:"overLimit,omitempty"`
+}
+
+// Publisher republishes a metadata file.
+type Publisher interface {
+	PublishMetadata() error
+}
+
+// TagUseCase renames, merges, deletes, adds and removes tags across quotes
+// and flyers.
+type TagUseCase struct {
+	tags   repository.TagRepository
+	quotes Publisher
+	images Publisher
+}
+
+func NewTagUseCase(tags repository.TagRepository, quotes, images Publisher) *TagUseCase {
+	return &TagUseCase{
+		tags:   tags,
+		quotes: quotes,
+		images: images,
+	}
+}
+
+// Apply runs a bulk operation in one transaction and republishes both
+// metadata files. A dry run only counts the items that would change.
+func (uc *TagUseCase) Apply(req BulkRequest) (*BulkResult, error) {
+	edit, selector, err := plan(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	withQuotes, withFlyers := tables(req)
+	result := &BulkResult{Op: req.Op, DryRun: req.DryRun}
+	err = uc.tags.Transaction(func(repo repository.TagRepository) error {
+		if withQuotes {
+			quotes, err := repo.FindQuotes(selector)
+			if err != nil {
+				return err
+			}
+			for _, q := range quotes {
+				tags, changed := edit(q.Tags)
+				if !changed {
+					continue
+				}
+				if quote.CheckTagCount(len(tags)) != "" {
+					result.OverLimit = append(result.OverLimit, q.Id)
+					continue
+				}
+				result.Quotes++
+				if !req.DryRun {
+					if err := repo.SetQuoteTags(q.Id, tags); err != nil {
+						return fmt.Errorf("failed to update tags of quote %d: %w", q.Id, err)
+					}
+				}
+			}
+			if len(result.OverLimit) > 0 && !req.DryRun {
+				return fmt.Errorf("%w: quotes %v would carry too many tags", ErrInvalidOperation, result.OverLimit)
+			}
+		}
+		if withFlyers {
+			flyers, err := repo.FindFlyers(selector)
+			if err != nil {
+				return err
+			}
+			for _, f := range flyers {
+				tags, changed := edit(f.Design.Tags)
+				if !changed {
+					continue
+				}
+				result.Flyers++
+				if !req.DryRun {
+					if err := repo.SetFlyerTags(f.Id, tags); err != nil {
+						return fmt.Errorf("failed to update tags of flyer %d: %w", f.Id, err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		return result, nil
+	}
+	if err := uc.quotes.PublishMetadata(); err != nil {
+		return nil, err
+	}
+	if err := uc.images.PublishMetadata(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// tagEdit rewrites an item's tags and reports whether they changed.
+type tagEdit func(tags []string) ([]string, bool)
+
+// plan validates the request and returns the edit to apply to every item
+// and the selector picking the items.
+func plan(req *BulkRequest) (tagEdit, repository.TagSelector, error) {
+	req.Op = strings.ToLower(strings.TrimSpace(req.Op))
+	req.Scope = strings.ToLower(strings.TrimSpace(req.Scope))
+	switch req.Scope {
+	case "", ScopeQuotes, ScopeFlyers:
+	default:
+		return nil, repository.TagSelector{}, fmt.Errorf("%w: unknown scope %q", ErrInvalidOperation, req.Scope)
+	}
+
+	tags := cleanTags(append([]string{req.Tag}, req.Tags...))
+	to := strings.TrimSpace(req.To)
+	selector := repository.TagSelector{
+		Lang:     strings.TrimSpace(req.Filter.Lang),
+		QuoteIDs: req.Filter.QuoteIDs,
+		FlyerIDs: req.Filter.FlyerIDs,
+	}
+	if filterTag := strings.TrimSpace(req.Filter.Tag); filterTag != "" {
+		selector.Tags = []string{filterTag}
+	}
+
+	if len(tags) == 0 {
+		return nil, selector, fmt.Errorf("%w: at least one tag is required", ErrInvalidOperation)
+	}
+	var edit tagEdit
+	switch req.Op {
+	case OpRename, OpMerge:
+		if req.Op == OpRename && len(tags) != 1 {
+			return nil, selector, fmt.Errorf("%w: rename takes exactly one tag, use merge for several", ErrInvalidOperation)
+		}
+		if to == "" {
+			return nil, selector, fmt.Errorf("%w: %s needs the new tag in \"to\"", ErrInvalidOperation, req.Op)
+		}
+		if msg := quote.CheckTag(to); msg != "" {
+			return nil, selector, fmt.Errorf("%w: to: %s", ErrInvalidOperation, msg)
+		}
+		edit = replaceTags(tags, to)
+	case OpDelete, OpRemove:
+		if req.Op == OpRemove && !hasFilter(req.Filter) {
+			return nil, selector, fmt.Errorf("%w: remove needs a filter; use delete to drop tags everywhere", ErrInvalidOperation)
+		}
+		edit = replaceTags(tags, "")
+	case OpAdd:
+		if !hasFilter(req.Filter) {
+			return nil, selector, fmt.Errorf("%w: add needs a filter", ErrInvalidOperation)
+		}
+		for _, t := range tags {
+			if msg := quote.CheckTag(t); msg != "" {
+				return nil, selector, fmt.Errorf("%w: tag %s", ErrInvalidOperation, msg)
+			}
+		}
+		return addTags(tags), selector, nil
+	default:
+		return nil, selector, fmt.Errorf("%w: unknown operation %q", ErrInvalidOperation, req.Op)
+	}
+
+	// only items carrying one of the tags can change; a filter tag narrows
+	// them further, so the edit leaves items without it alone
+	if len(selector.Tags) > 0 {
+		edit = requireTag(selector.Tags[0], edit)
+	}
+	selector.Tags = tags
+	return edit, selector, nil
+}
+
+// tables reports which tables an operation touches: those in its scope,
+// minus the other table when the filter names IDs of only one.
+func tables(req BulkRequest) (quotes, flyers bool) {
+	quotes, flyers = req.Scope != ScopeFlyers, req.Scope != ScopeQuotes
+	if len(req.Filter.QuoteIDs) > 0 && len(req.Filter.FlyerIDs) == 0 {
+		flyers = false
+	}
+	if len(req.Filter.FlyerIDs) > 0 && len(req.Filter.QuoteIDs) == 0 {
+		quotes = false
+	}
+	return quotes, flyers
+}
+
+func hasFilter(f Filter) bool {
+	return strings.TrimSpace(f.Tag) != "" || strings.TrimSpace(f.Lang) != "" || len(f.QuoteIDs) > 0 || len(f.FlyerIDs) > 0
+}
+
+// replaceTags drops every tag matching one of from and, when to is set, puts
+// to in place of the first of them.
+func replaceTags(from []string, to string) tagEdit {
+	return func(tags []string) ([]string, bool) {
+		out := make([]string, 0, len(tags))
+		changed := false
+		for _, t := range tags {
+			if !containsFold(from, t) {
+				out = append(out, t)
+				continue
+			}
+			if to != "" && !containsFold(out, to) {
+				out = append(out, to)
+			}
+			changed = changed || t != to
+		}
+		out = cleanTags(out)
+		return out, changed || len(out) != len(tags)
+	}
+}
+
+func addTags(add []string) tagEdit {
+	return func(tags []string) ([]string, bool) {
+		out := append([]string(nil), tags...)
+		for _, t := range add {
+			if !containsFold(out, t) {
+				out = append(out, t)
+			}
+		}
+		return out, len(out) != len(tags)
+	}
+}
+
+// requireTag applies edit only to items that also carry tag.
+func requireTag(tag string, edit tagEdit) tagEdit {
+	return func(tags []string) ([]string, bool) {
+		if !containsFold(tags, tag) {
+			return tags, false
+		}
+		return edit(tags)
+	}
+}
+
+// cleanTags trims tags and drops empty and case-insensitively repeated ones.
+func cleanTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t != "" && !containsFold(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
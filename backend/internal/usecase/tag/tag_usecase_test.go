@@ -0,0 +1,208 @@
+package tag
+
+import (
+	"backend/internal/domain/entity"
+	"backend/internal/domain/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fakeTagRepo struct {
+	quotes []entity.Quote
+	flyers []entity.Flyer
+	// failFlyer makes updates of this flyer fail.
+	failFlyer uint
+}
+
+func (r *fakeTagRepo) matches(tags []string, lang string, sel repository.TagSelector) bool {
+	if sel.Lang != "" && !strings.EqualFold(lang, sel.Lang) {
+		return false
+	}
+	if len(sel.Tags) == 0 {
+		return true
+	}
+	for _, t := range tags {
+		if containsFold(sel.Tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeTagRepo) FindQuotes(sel repository.TagSelector) ([]entity.Quote, error) {
+	var found []entity.Quote
+	for _, q := range r.quotes {
+		if r.matches(q.Tags, q.Lang, sel) && (len(sel.QuoteIDs) == 0 || containsID(sel.QuoteIDs, q.Id)) {
+			found = append(found, q)
+		}
+	}
+	return found, nil
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeTagRepo) FindFlyers(sel repository.TagSelector) ([]entity.Flyer, error) {
+	var found []entity.Flyer
+	for _, f := range r.flyers {
+		if r.matches(f.Design.Tags, f.Lang, sel) {
+			found = append(found, f)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeTagRepo) SetQuoteTags(id int, tags []string) error {
+	r.quotes[id-1].Tags = tags
+	r.quotes[id-1].Version++
+	return nil
+}
+
+func (r *fakeTagRepo) SetFlyerTags(id uint, tags []string) error {
+	if id == r.failFlyer {
+		return errors.New("connection reset")
+	}
+	r.flyers[id-1].Design.Tags = tags
+	return nil
+}
+
+// Transaction restores both tables when fn fails.
+func (r *fakeTagRepo) Transaction(fn func(repository.TagRepository) error) error {
+	quotes := make([]entity.Quote, len(r.quotes))
+	for i, q := range r.quotes {
+		q.Tags = append([]string(nil), q.Tags...)
+		quotes[i] = q
+	}
+	flyers := make([]entity.Flyer, len(r.flyers))
+	for i, f := range r.flyers {
+		f.Design.Tags = append([]string(nil), f.Design.Tags...)
+		flyers[i] = f
+	}
+	if err := fn(r); err != nil {
+		r.quotes, r.flyers = quotes, flyers
+		return err
+	}
+	return nil
+}
+
+type fakePublisher struct{ published int }
+
+func (p *fakePublisher) PublishMetadata() error {
+	p.published++
+	return nil
+}
+
+func newFixture() (*TagUseCase, *fakeTagRepo, *fakePublisher, *fakePublisher) {
+	repo := &fakeTagRepo{
+		quotes: []entity.Quote{
+			{Id: 1, Lang: "en-US", Tags: []string{"Summer", "sale"}, Version: 1},
+			{Id: 2, Lang: "fr-FR", Tags: []string{"summer"}, Version: 1},
+			{Id: 3, Lang: "en-US", Tags: []string{"winter"}, Version: 1},
+		},
+		flyers: []entity.Flyer{
+			{Id: 1, Lang: "en-US", Design: entity.Design{Tags: []string{"summer", "summer-24"}}},
+			{Id: 2, Lang: "en-US", Design: entity.Design{Tags: []string{"autumn"}}},
+		},
+	}
+	quotes, images := &fakePublisher{}, &fakePublisher{}
+	return NewTagUseCase(repo, quotes, images), repo, quotes, images
+}
+
+func TestRenameAcrossQuotesAndFlyers(t *testing.T) {
+	uc, repo, quotes, images := newFixture()
+
+	result, err := uc.Apply(BulkRequest{Op: OpRename, Tag: "summer", To: "summer-24"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Quotes != 2 || result.Flyers != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if got := strings.Join(repo.quotes[0].Tags, ","); got != "summer-24,sale" || repo.quotes[0].Version != 2 {
+		t.Errorf("unexpected quote 1: %+v", repo.quotes[0])
+	}
+	if got := strings.Join(repo.flyers[0].Design.Tags, ","); got != "summer-24" {
+		t.Errorf("expected the renamed tag to be merged into the existing one, got %s", got)
+	}
+	if quotes.published != 1 || images.published != 1 {
+		t.Errorf("expected both metadata files to be republished, got %d and %d", quotes.published, images.published)
+	}
+}
+
+func TestDryRunCountsWithoutWriting(t *testing.T) {
+	uc, repo, quotes, _ := newFixture()
+
+	result, err := uc.Apply(BulkRequest{Op: OpMerge, Tags: []string{"summer", "winter"}, To: "seasonal", Filter: Filter{Lang: "en-US"}, DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Quotes != 2 || result.Flyers != 1 || !result.DryRun {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if repo.quotes[2].Tags[0] != "winter" || quotes.published != 0 {
+		t.Errorf("expected nothing to be written or published")
+	}
+}
+
+func TestAddAndRemoveNeedAFilter(t *testing.T) {
+	uc, repo, _, _ := newFixture()
+
+	for _, op := range []string{OpAdd, OpRemove} {
+		if _, err := uc.Apply(BulkRequest{Op: op, Tags: []string{"x"}}); !errors.Is(err, ErrInvalidOperation) {
+			t.Errorf("%s: expected ErrInvalidOperation, got %v", op, err)
+		}
+	}
+
+	result, err := uc.Apply(BulkRequest{Op: OpAdd, Tags: []string{"featured"}, Filter: Filter{QuoteIDs: []int{3}}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Quotes != 1 || result.Flyers != 0 || len(repo.quotes[2].Tags) != 2 {
+		t.Errorf("expected only quote 3 to be tagged, got %+v", result)
+	}
+}
+
+func TestFailedOperationRollsBack(t *testing.T) {
+	uc, repo, quotes, _ := newFixture()
+	repo.failFlyer = 1
+
+	if _, err := uc.Apply(BulkRequest{Op: OpDelete, Tag: "summer"}); err == nil {
+		t.Fatal("expected the flyer update to fail")
+	}
+	if len(repo.quotes[0].Tags) != 2 || repo.quotes[0].Version != 1 || quotes.published != 0 {
+		t.Errorf("expected the quote changes to be rolled back, got %+v", repo.quotes[0])
+	}
+}
+
+func TestAddRespectsTheTagLimit(t *testing.T) {
+	uc, repo, quotes, _ := newFixture()
+	for i := len(repo.quotes[0].Tags); i < 20; i++ {
+		repo.quotes[0].Tags = append(repo.quotes[0].Tags, fmt.Sprintf("tag-%d", i))
+	}
+	req := BulkRequest{Op: OpAdd, Tags: []string{"featured"}, Filter: Filter{QuoteIDs: []int{1, 3}}}
+
+	req.DryRun = true
+	result, err := uc.Apply(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Quotes != 1 || len(result.OverLimit) != 1 || result.OverLimit[0] != 1 {
+		t.Errorf("expected quote 1 to be reported over the limit, got %+v", result)
+	}
+
+	req.DryRun = false
+	if _, err := uc.Apply(req); !errors.Is(err, ErrInvalidOperation) {
+		t.Fatalf("expected ErrInvalidOperation, got %v", err)
+	}
+	if len(repo.quotes[0].Tags) != 20 || len(repo.quotes[2].Tags) != 1 || quotes.published != 0 {
+		t.Errorf("expected nothing to be written, got %v and %v", repo.quotes[0].Tags, repo.quotes[2].Tags)
+	}
+}
@@ -1,29 +1,83 @@
 package internal
 
 import (
+    "backend/internal/config"
     "backend/internal/delivery/http/handler"
+    "backend/internal/infrastructure/googlesheets"
     "backend/internal/infrastructure/metadata"
     "backend/internal/infrastructure/persistence/postgres"
     "backend/internal/infrastructure/s3"
+    "backend/internal/usecase/catalog"
     "backend/internal/usecase/image"
+    "backend/internal/usecase/quote"
+    "backend/internal/usecase/tag"
     "gorm.io/gorm"
 )
 
-func InitializeImageHandler(db *gorm.DB) (*handler.ImageHandler, error) {
+// Services holds the use cases shared by several handlers. They are built
+// once, so every handler uploads and publishes through the same S3 and
+// metadata services.
+type Services struct {
+    Images *image.ImageUseCase
+    Quotes *quote.QuoteUseCase
+}
+
+func InitializeServices(db *gorm.DB, cfg *config.Config) (*Services, error) {
     // Create infrastructure services
     s3Service, err := s3.NewS3Service()
     if err != nil {
         return nil, err
     }
-    
-    metadataService := metadata.NewMetadataService(s3Service)
+
+    metadataService := metadata.NewMetadataService(s3Service, postgres.NewCatalogRepository(db), cfg.MetadataSnapshotsKeep)
+    urlResolver := s3.NewURLResolver(cfg, s3Service)
+    sheetsService := googlesheets.NewSheetsService(cfg)
     imageRepo := postgres.NewImageRepository(db)
-    
-    // Create use case
-    imageUseCase := image.NewImageUseCase(imageRepo, s3Service, metadataService)
-    
-    // Create handler
-    imageHandler := handler.NewImageHandler(imageUseCase)
-    
-    return imageHandler, nil
-} 
\ No newline at end of file
+    quoteRepo := postgres.NewQuoteRepository(db)
+    profileRepo := postgres.NewImportProfileRepository(db)
+
+    // Create use cases
+    return &Services{
+        Images: image.NewImageUseCase(imageRepo, s3Service, metadataService, urlResolver),
+        Quotes: quote.NewQuoteUseCase(quoteRepo, profileRepo, sheetsService, metadataService),
+    }, nil
+}
+
+func InitializeImageHandler(services *Services) *handler.ImageHandler {
+    return handler.NewImageHandler(services.Images)
+}
+
+func InitializeQuoteHandler(services *Services) *handler.QuoteHandler {
+    return handler.NewQuoteHandler(services.Quotes)
+}
+
+// InitializeSyncHandler also returns the sync use case so the caller can
+// start its scheduler.
+func InitializeSyncHandler(db *gorm.DB, services *Services) (*handler.SyncHandler, *quote.SyncUseCase) {
+    syncRepo := postgres.NewSyncSourceRepository(db)
+    syncUseCase := quote.NewSyncUseCase(services.Quotes, syncRepo)
+
+    return handler.NewSyncHandler(syncUseCase), syncUseCase
+}
+
+// InitializeDailyHandler also returns the daily use case so the caller can
+// start its publisher.
+func InitializeDailyHandler(db *gorm.DB, cfg *config.Config, services *Services) (*handler.DailyHandler, *quote.DailyUseCase) {
+    dailyRepo := postgres.NewDailyQuoteRepository(db)
+    dailyUseCase := quote.NewDailyUseCase(services.Quotes, dailyRepo, cfg.DailyQuoteDays)
+
+    return handler.NewDailyHandler(dailyUseCase), dailyUseCase
+}
+
+func InitializeTagHandler(db *gorm.DB, services *Services) *handler.TagHandler {
+    tagRepo := postgres.NewTagRepository(db)
+    tagUseCase := tag.NewTagUseCase(tagRepo, services.Quotes, services.Images)
+
+    return handler.NewTagHandler(tagUseCase)
+}
+
+func InitializeMetadataHandler(db *gorm.DB, cfg *config.Config) (*handler.MetadataHandler, error) {
+    catalogUseCase := catalog.NewCatalogUseCase(postgres.NewCatalogRepository(db))
+
+    return handler.NewMetadataHandler(catalogUseCase), nil
+}
@@ -21,9 +21,9 @@ func FetchImageFromDB(imageId uint) (*images.Flyer, error) {
 	// Fetch the image from the database by ID
 	if err := db.First(&image, imageId).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("quote with ID %d not found", imageId)
+			return nil, fmt.Errorf("image with ID %d not found", imageId)
 		}
-		return nil, fmt.Errorf("failed to fetch quote: %w", err)
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
 	}
 
 	// a standalone module which conveters the given data into JSON format